@@ -0,0 +1,139 @@
+package csvmum
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// binding maps the columns of a header to the fields of a struct, and decodes
+// records with that header into it.
+type binding struct {
+	codec *codec
+	// fieldList holds the field of each column, or -1 if it has none
+	fieldList []int
+	// columns holds the column of each field, or -1 if it has none
+	columns     []int
+	header      []string
+	extra       []int
+	diagnostics []HeaderDiagnostic
+}
+
+// newBinding maps header hh to the fields of c with the header policy of o.
+// Errors are not prefixed, since they are returned by both the typed and
+// dynamic readers.
+func newBinding(c *codec, hh []string, o options) (binding, error) {
+	if c.decodeErr != nil {
+		return binding{}, c.decodeErr
+	}
+
+	fields := c.fields
+	b := binding{codec: c}
+
+	hm := mapHeader(fields, hh, o.headerPolicy)
+	b.fieldList = hm.fieldList
+	b.diagnostics = hm.diagnostics
+
+	if c.extra != nil {
+		b.header = slices.Clone(hh)
+		for i, j := range b.fieldList {
+			if j == -1 {
+				b.extra = append(b.extra, i)
+			}
+		}
+
+		// unmapped columns are expected when there is an extra field
+		b.diagnostics = slices.DeleteFunc(b.diagnostics, func(d HeaderDiagnostic) bool {
+			return d.Kind == UnknownColumn
+		})
+	}
+
+	for j, fd := range fields {
+		if !hm.found[j] && fd.required {
+			return b, fmt.Errorf("missing required column: %s", fd.name)
+		}
+	}
+
+	b.columns = make([]int, len(fields))
+	for j := range b.columns {
+		b.columns[j] = -1
+	}
+	for i, j := range b.fieldList {
+		if j != -1 {
+			b.columns[j] = i
+		}
+	}
+
+	return b, nil
+}
+
+// strict returns a *HeaderError if the policy of o is HeaderStrict and the
+// header has diagnostics.
+func (b *binding) strict(o options) error {
+	if o.headerPolicy == HeaderStrict && len(b.diagnostics) > 0 {
+		return &HeaderError{Diagnostics: b.diagnostics}
+	}
+	return nil
+}
+
+// decode decodes record r into v, which must already be zero, and calls its
+// AfterUnmarshalCSV method. generated, if not nil, is v's generated
+// unmarshaler. line returns the line of column i.
+func (b *binding) decode(v reflect.Value, generated RowUnmarshaler, r []string, line func(i int) int) error {
+	if generated != nil {
+		if err := generated.UnmarshalCSVRow(r, b.columns); err != nil {
+			var fe *FieldError
+			if errors.As(err, &fe) {
+				return b.parseError(v.Type(), r, fe.Index, fe.Err, line)
+			}
+			return fmt.Errorf("cannot unmarshal: %w", err)
+		}
+	} else {
+		for j, i := range b.columns {
+			fd := b.codec.fields[j]
+			if i == -1 && !fd.hasDefault {
+				continue
+			}
+
+			var s string
+			if i != -1 {
+				s = r[i]
+			}
+
+			if err := b.codec.decoders[j](v.FieldByIndex(fd.index), s); err != nil {
+				return b.parseError(v.Type(), r, j, err, line)
+			}
+		}
+	}
+
+	if len(b.extra) > 0 {
+		extra := make(map[string]string, len(b.extra))
+		for _, i := range b.extra {
+			extra[b.header[i]] = r[i]
+		}
+		v.FieldByIndex(b.codec.extra).Set(reflect.ValueOf(extra))
+	}
+
+	return afterUnmarshal(v, line(0))
+}
+
+// parseError describes a failure to decode field j of record r into a t.
+func (b *binding) parseError(t reflect.Type, r []string, j int, err error, line func(i int) int) error {
+	fd := b.codec.fields[j]
+	i := b.columns[j]
+
+	var value string
+	if i != -1 {
+		value = r[i]
+	}
+
+	return &ParseError{
+		Line:   line(max(i, 0)),
+		Column: i + 1,
+		Header: fd.name,
+		Field:  fd.goName(t),
+		Value:  value,
+		Err:    err,
+	}
+}
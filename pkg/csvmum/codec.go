@@ -0,0 +1,336 @@
+package csvmum
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type decodeFunc func(v reflect.Value, s string) error
+
+type encodeFunc func(v reflect.Value) (string, error)
+
+// codec is the compiled plan for a struct type: its fields, with a decoder
+// and an encoder for each. Codecs are built once per type and cached.
+type codec struct {
+	fields   []field
+	decoders []decodeFunc
+	encoders []encodeFunc
+	// rawDecoders and rawEncoders leave out defaults, required checks and
+	// omitempty, so that a value round trips through them unchanged
+	rawDecoders []decodeFunc
+	rawEncoders []encodeFunc
+
+	// extra is the index of the struct field that collects unmapped
+	// columns, or nil
+	extra []int
+
+	// typeParsers is set if any field uses a parser registered by type,
+	// which generated code does not know about
+	typeParsers bool
+	// decodeErr and encodeErr are the errors for the first field that
+	// cannot be unmarshaled or marshaled, whose decoder or encoder is nil
+	decodeErr error
+	encodeErr error
+}
+
+var codecs sync.Map
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func codecFor(t reflect.Type) (*codec, error) {
+	if c, ok := codecs.Load(t); ok {
+		return c.(*codec), nil
+	}
+
+	all, err := buildFieldList(t)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]field, 0, len(all))
+	var extra []int
+	for _, fd := range all {
+		if fd.extra {
+			extra = fd.index
+			continue
+		}
+		fields = append(fields, fd)
+	}
+
+	c := &codec{
+		fields:      fields,
+		decoders:    make([]decodeFunc, len(fields)),
+		encoders:    make([]encodeFunc, len(fields)),
+		rawDecoders: make([]decodeFunc, len(fields)),
+		rawEncoders: make([]encodeFunc, len(fields)),
+		extra:       extra,
+	}
+	for i, fd := range fields {
+		ft := t.FieldByIndex(fd.index).Type
+		if dec, err := compileFieldDecoder(ft, fd.tagOptions); err == nil {
+			c.decoders[i] = fd.decoder(dec)
+			c.rawDecoders[i] = dec
+		} else if c.decodeErr == nil {
+			c.decodeErr = fmt.Errorf("field %s: %w", fd.name, err)
+		}
+		if enc, err := compileFieldEncoder(ft, fd.tagOptions); err == nil {
+			c.encoders[i] = fd.encoder(enc)
+			c.rawEncoders[i] = enc
+		} else if c.encodeErr == nil {
+			c.encodeErr = fmt.Errorf("field %s: %w", fd.name, err)
+		}
+		c.typeParsers = c.typeParsers || (fd.parser == "" && hasTypeParser(ft))
+	}
+
+	cc, _ := codecs.LoadOrStore(t, c)
+	return cc.(*codec), nil
+}
+
+// compileFieldDecoder compiles the decoder for a field of type t, using the
+// parser and separator from opts.
+func compileFieldDecoder(t reflect.Type, opts tagOptions) (decodeFunc, error) {
+	if isList(t) {
+		dec, err := compileFieldDecoder(t.Elem(), tagOptions{parser: opts.parser})
+		if err != nil {
+			return nil, err
+		}
+		return listDecoder(t, dec, opts.separator()), nil
+	}
+
+	name := opts.parser
+	if name == "" {
+		return compileDecoder(t)
+	}
+
+	if t.Kind() == reflect.Pointer {
+		dec, err := compileFieldDecoder(t.Elem(), opts)
+		if err != nil {
+			return nil, err
+		}
+		return pointerDecoder(t.Elem(), dec), nil
+	}
+
+	p, err := namedParser(name, t)
+	if err != nil {
+		return nil, err
+	}
+	return p.decode, nil
+}
+
+// compileFieldEncoder compiles the encoder for a field of type t, using the
+// parser and separator from opts.
+func compileFieldEncoder(t reflect.Type, opts tagOptions) (encodeFunc, error) {
+	if isList(t) {
+		enc, err := compileFieldEncoder(t.Elem(), tagOptions{parser: opts.parser})
+		if err != nil {
+			return nil, err
+		}
+		return listEncoder(enc, opts.separator()), nil
+	}
+
+	name := opts.parser
+	if name == "" {
+		return compileEncoder(t)
+	}
+
+	if t.Kind() == reflect.Pointer {
+		enc, err := compileFieldEncoder(t.Elem(), opts)
+		if err != nil {
+			return nil, err
+		}
+		return pointerEncoder(enc), nil
+	}
+
+	p, err := namedParser(name, t)
+	if err != nil {
+		return nil, err
+	}
+	return p.encode, nil
+}
+
+func pointerDecoder(et reflect.Type, dec decodeFunc) decodeFunc {
+	return func(v reflect.Value, s string) error {
+		if s == "" {
+			v.SetZero()
+			return nil
+		}
+
+		p := reflect.New(et)
+		if err := dec(p.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+}
+
+func pointerEncoder(enc encodeFunc) encodeFunc {
+	return func(v reflect.Value) (string, error) {
+		if v.IsNil() {
+			return "", nil
+		}
+		return enc(v.Elem())
+	}
+}
+
+func compileDecoder(t reflect.Type) (decodeFunc, error) {
+	if t.Kind() == reflect.Pointer {
+		dec, err := compileDecoder(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return pointerDecoder(t.Elem(), dec), nil
+	}
+
+	if p, ok := typeParser(t); ok {
+		return p.decode, nil
+	}
+
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return func(v reflect.Value, s string) error {
+			return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		}, nil
+	}
+
+	if t == durationType {
+		return func(v reflect.Value, s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return fmt.Errorf("error parsing duration: %w", err)
+			}
+			v.SetInt(int64(d))
+			return nil
+		}, nil
+	}
+
+	kind := t.Kind()
+	switch kind {
+	case reflect.String:
+		return func(v reflect.Value, s string) error {
+			v.SetString(s)
+			return nil
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := t.Bits()
+		return func(v reflect.Value, s string) error {
+			i, err := strconv.ParseInt(s, 10, bits)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", kind, err)
+			}
+			v.SetInt(i)
+			return nil
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		bits := t.Bits()
+		return func(v reflect.Value, s string) error {
+			u, err := strconv.ParseUint(s, 10, bits)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", kind, err)
+			}
+			v.SetUint(u)
+			return nil
+		}, nil
+	case reflect.Bool:
+		return func(v reflect.Value, s string) error {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", kind, err)
+			}
+			v.SetBool(b)
+			return nil
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		bits := t.Bits()
+		return func(v reflect.Value, s string) error {
+			f, err := strconv.ParseFloat(s, bits)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", kind, err)
+			}
+			v.SetFloat(f)
+			return nil
+		}, nil
+	case reflect.Slice:
+		if isBytes(t) {
+			// as for lists, an empty cell is a nil slice
+			return func(v reflect.Value, s string) error {
+				if s == "" {
+					v.SetZero()
+					return nil
+				}
+				v.SetBytes([]byte(s))
+				return nil
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported type: %s", t)
+}
+
+func compileEncoder(t reflect.Type) (encodeFunc, error) {
+	if t.Kind() == reflect.Pointer {
+		enc, err := compileEncoder(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return pointerEncoder(enc), nil
+	}
+
+	if p, ok := typeParser(t); ok {
+		return p.encode, nil
+	}
+
+	if reflect.PointerTo(t).Implements(textMarshalerType) {
+		return func(v reflect.Value) (string, error) {
+			b, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		}, nil
+	}
+
+	if t == durationType {
+		return func(v reflect.Value) (string, error) {
+			return time.Duration(v.Int()).String(), nil
+		}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(v reflect.Value) (string, error) {
+			return v.String(), nil
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value) (string, error) {
+			return strconv.FormatInt(v.Int(), 10), nil
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(v reflect.Value) (string, error) {
+			return strconv.FormatUint(v.Uint(), 10), nil
+		}, nil
+	case reflect.Bool:
+		return func(v reflect.Value) (string, error) {
+			return strconv.FormatBool(v.Bool()), nil
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		bits := t.Bits()
+		return func(v reflect.Value) (string, error) {
+			return strconv.FormatFloat(v.Float(), 'f', -1, bits), nil
+		}, nil
+	case reflect.Slice:
+		if isBytes(t) {
+			return func(v reflect.Value) (string, error) {
+				return string(v.Bytes()), nil
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported type: %s", t)
+}
@@ -0,0 +1,483 @@
+package csvmum
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecFor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cached", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			First  string
+			Second *int `csv:"second,default=2"`
+		}
+
+		c1, err := codecFor(reflect.TypeOf(testType{}))
+		assert.Nil(err)
+
+		c2, err := codecFor(reflect.TypeOf(testType{}))
+		assert.Nil(err)
+
+		assert.Same(c1, c2)
+		assert.Equal([]field{
+			{name: "First", index: []int{0}},
+			{name: "second", index: []int{1}, tagOptions: tagOptions{hasDefault: true, defaultValue: "2"}},
+		}, c1.fields)
+		assert.Len(c1.decoders, 2)
+		assert.Len(c1.encoders, 2)
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		c, err := codecFor(reflect.TypeOf(1))
+
+		assert.Nil(c)
+		assert.EqualError(err, "cannot get headers: not a struct")
+	})
+}
+
+func TestCompileDecoder(t *testing.T) {
+	t.Parallel()
+
+	one := 1
+
+	tt := []struct {
+		name     string
+		value    any
+		input    string
+		expected any
+		err      string
+	}{{
+		name:     "string",
+		value:    "",
+		input:    "one",
+		expected: "one",
+	}, {
+		name:     "int16",
+		value:    int16(0),
+		input:    "-16",
+		expected: int16(-16),
+	}, {
+		name:  "int16 overflow",
+		value: int16(0),
+		input: "65536",
+		err:   "error parsing int16: strconv.ParseInt: parsing \"65536\": value out of range",
+	}, {
+		name:     "uint32",
+		value:    uint32(0),
+		input:    "32",
+		expected: uint32(32),
+	}, {
+		name:     "float32",
+		value:    float32(0),
+		input:    "1.5",
+		expected: float32(1.5),
+	}, {
+		name:     "bool",
+		value:    false,
+		input:    "true",
+		expected: true,
+	}, {
+		name:     "duration",
+		value:    time.Duration(0),
+		input:    "2m",
+		expected: 2 * time.Minute,
+	}, {
+		name:     "pointer",
+		value:    (*int)(nil),
+		input:    "1",
+		expected: &one,
+	}, {
+		name:     "nil pointer",
+		value:    &one,
+		input:    "",
+		expected: (*int)(nil),
+	}, {
+		name:     "text unmarshaler",
+		value:    customMarshalAndUnmarshal{},
+		input:    "~one~",
+		expected: customMarshalAndUnmarshal{One: "one"},
+	}, {
+		name:  "unsupported",
+		value: []int{},
+		input: "1",
+		err:   "unsupported type: []int",
+	}}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			v := reflect.New(reflect.TypeOf(tc.value)).Elem()
+			v.Set(reflect.ValueOf(tc.value))
+
+			dec, err := compileDecoder(v.Type())
+			if err == nil {
+				err = dec(v, tc.input)
+			}
+			if tc.err != "" {
+				assert.EqualError(err, tc.err)
+				return
+			}
+
+			assert.Nil(err)
+			assert.Equal(tc.expected, v.Interface())
+		})
+	}
+}
+
+func TestCompileEncoder(t *testing.T) {
+	t.Parallel()
+
+	one := 1
+
+	tt := []struct {
+		name     string
+		value    any
+		expected string
+		err      string
+	}{{
+		name:     "string",
+		value:    "one",
+		expected: "one",
+	}, {
+		name:     "int16",
+		value:    int16(-16),
+		expected: "-16",
+	}, {
+		name:     "uint32",
+		value:    uint32(32),
+		expected: "32",
+	}, {
+		name:     "float32",
+		value:    float32(0.1),
+		expected: "0.1",
+	}, {
+		name:     "bool",
+		value:    true,
+		expected: "true",
+	}, {
+		name:     "duration",
+		value:    2 * time.Minute,
+		expected: "2m0s",
+	}, {
+		name:     "pointer",
+		value:    &one,
+		expected: "1",
+	}, {
+		name:     "nil pointer",
+		value:    (*int)(nil),
+		expected: "",
+	}, {
+		name:     "text marshaler",
+		value:    customMarshalAndUnmarshal{One: "one"},
+		expected: "~one~",
+	}, {
+		name:  "unsupported",
+		value: []int{},
+		err:   "unsupported type: []int",
+	}}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			v := reflect.New(reflect.TypeOf(tc.value)).Elem()
+			v.Set(reflect.ValueOf(tc.value))
+
+			var s string
+			enc, err := compileEncoder(v.Type())
+			if err == nil {
+				s, err = enc(v)
+			}
+			if tc.err != "" {
+				assert.EqualError(err, tc.err)
+				return
+			}
+
+			assert.Nil(err)
+			assert.Equal(tc.expected, s)
+		})
+	}
+}
+
+type benchTime struct {
+	time.Duration
+}
+
+func (t benchTime) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *benchTime) UnmarshalText(text []byte) error {
+	d, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	t.Duration = d
+	return nil
+}
+
+// benchStopTime mirrors the shape of gtfs.StopTime
+type benchStopTime struct {
+	TripID            string    `csv:"trip_id"`
+	ArrivalTime       benchTime `csv:"arrival_time"`
+	DepartureTime     benchTime `csv:"departure_time"`
+	StopID            string    `csv:"stop_id"`
+	StopSequence      int       `csv:"stop_sequence"`
+	StopHeadsign      string    `csv:"stop_headsign"`
+	PickupType        *int      `csv:"pickup_type"`
+	DropOffType       *int      `csv:"drop_off_type"`
+	ShapeDistTraveled *float64  `csv:"shape_dist_traveled"`
+	Timepoint         *int      `csv:"timepoint"`
+}
+
+const benchRows = 1000
+
+func benchInput() []byte {
+	b := &bytes.Buffer{}
+	b.WriteString("trip_id,arrival_time,departure_time,stop_id,stop_sequence,stop_headsign,pickup_type,drop_off_type,shape_dist_traveled,timepoint\n")
+	for i := range benchRows {
+		fmt.Fprintf(b, "trip_%d,8h%dm,8h%dm30s,stop_%d,%d,Downtown,0,,%d.25,1\n", i/20, i%60, i%60, i, i%20, i)
+	}
+	return b.Bytes()
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	input := benchInput()
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+
+	for range b.N {
+		um, err := NewUnmarshaler[benchStopTime](bytes.NewReader(input))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		var r benchStopTime
+		for {
+			err := um.Unmarshal(&r)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkUnmarshalReflect measures the per-row reflection path csvmum used
+// before codecs were compiled, as a baseline for BenchmarkUnmarshal.
+func BenchmarkUnmarshalReflect(b *testing.B) {
+	input := benchInput()
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+
+	for range b.N {
+		r := csv.NewReader(bytes.NewReader(input))
+
+		hh, err := r.Read()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		fields, _ := buildFieldList(reflect.TypeOf(benchStopTime{}))
+		fm := map[string]int{}
+		for _, fd := range fields {
+			fm[fd.name] = fd.index[0]
+		}
+		fieldList := make([]int, len(hh))
+		for i, h := range hh {
+			fieldList[i] = fm[h]
+		}
+
+		for {
+			row, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			var record benchStopTime
+			n := reflect.New(reflect.TypeOf(record)).Elem()
+			for i, j := range fieldList {
+				if err := reflectUnmarshalField(n.Field(j), row[i]); err != nil {
+					b.Fatal(err)
+				}
+			}
+			reflect.ValueOf(&record).Elem().Set(n)
+		}
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	um, _ := NewUnmarshaler[benchStopTime](bytes.NewReader(benchInput()))
+	records, err := um.ReadAll()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+
+	for range b.N {
+		m, _ := NewMarshaler[benchStopTime](io.Discard)
+		for _, r := range records {
+			if err := m.Marshal(r); err != nil {
+				b.Fatal(err)
+			}
+		}
+		m.Flush()
+	}
+}
+
+// BenchmarkMarshalReflect is the per-row reflection baseline for
+// BenchmarkMarshal.
+func BenchmarkMarshalReflect(b *testing.B) {
+	um, _ := NewUnmarshaler[benchStopTime](bytes.NewReader(benchInput()))
+	records, err := um.ReadAll()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	fields, _ := buildFieldList(reflect.TypeOf(benchStopTime{}))
+
+	b.ReportAllocs()
+
+	for range b.N {
+		w := csv.NewWriter(io.Discard)
+		for _, record := range records {
+			v := reflect.ValueOf(&record).Elem()
+			row := make([]string, len(fields))
+			for i, fd := range fields {
+				s, err := reflectMarshalField(v.Field(fd.index[0]))
+				if err != nil {
+					b.Fatal(err)
+				}
+				row[i] = s
+			}
+			w.Write(row)
+		}
+		w.Flush()
+	}
+}
+
+func reflectUnmarshalField(f reflect.Value, s string) error {
+	if f.Kind() == reflect.Pointer {
+		if s == "" {
+			f.SetZero()
+			return nil
+		}
+
+		p := reflect.New(f.Type().Elem())
+		if err := reflectUnmarshalField(p.Elem(), s); err != nil {
+			return err
+		}
+		f.Set(p)
+		return nil
+	}
+
+	if m, ok := f.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return m.UnmarshalText([]byte(s))
+	}
+
+	if f.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("error parsing duration: %w", err)
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, f.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", f.Kind(), err)
+		}
+		f.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(s, 10, f.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", f.Kind(), err)
+		}
+		f.SetUint(u)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", f.Kind(), err)
+		}
+		f.SetBool(b)
+	case reflect.Float32, reflect.Float64:
+		fl, err := strconv.ParseFloat(s, f.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %w", f.Kind(), err)
+		}
+		f.SetFloat(fl)
+	default:
+		return fmt.Errorf("unsupported type: %s", f.Type())
+	}
+
+	return nil
+}
+
+func reflectMarshalField(f reflect.Value) (string, error) {
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() {
+			return "", nil
+		}
+		return reflectMarshalField(f.Elem())
+	}
+
+	if m, ok := f.Addr().Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	if f.Type() == durationType {
+		return time.Duration(f.Int()).String(), nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		return f.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(f.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(f.Uint(), 10), nil
+	case reflect.Bool:
+		return strconv.FormatBool(f.Bool()), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(f.Float(), 'f', -1, f.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("unsupported type: %s", f.Type())
+}
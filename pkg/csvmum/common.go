@@ -3,61 +3,263 @@ package csvmum
 import (
 	"fmt"
 	"reflect"
-	"sort"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
-func buildFieldMap(t reflect.Type) (map[string]int, error) {
-	headers := map[string]int{}
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	extraType    = reflect.TypeOf(map[string]string(nil))
+)
+
+type field struct {
+	name string
+	// index is the path to the field, as for reflect.Value.FieldByIndex
+	index []int
+	// position is the value of the order= option
+	position int
+	tagOptions
+}
+
+type tagOptions struct {
+	omitEmpty    bool
+	required     bool
+	hasDefault   bool
+	defaultValue string
+	parser       string
+	extra        bool
+	hasPrefix    bool
+	prefix       string
+	sep          string
+	hasOrder     bool
+	order        string
+}
+
+func (fd field) decoder(dec decodeFunc) decodeFunc {
+	if !fd.hasDefault && !fd.required {
+		return dec
+	}
+
+	return func(v reflect.Value, s string) error {
+		if s == "" && fd.hasDefault {
+			s = fd.defaultValue
+		}
+		if s == "" && fd.required {
+			return ErrRequired
+		}
+		return dec(v, s)
+	}
+}
+
+func (fd field) encoder(enc encodeFunc) encodeFunc {
+	if !fd.omitEmpty {
+		return enc
+	}
+
+	return func(v reflect.Value) (string, error) {
+		if v.IsZero() {
+			return "", nil
+		}
+		return enc(v)
+	}
+}
+
+func buildFieldList(t reflect.Type) ([]field, error) {
+	fields := []field{}
 
 	if t.Kind() != reflect.Struct {
-		return headers, fmt.Errorf("cannot get headers: not a struct")
+		return fields, fmt.Errorf("cannot get headers: not a struct")
+	}
+
+	fields, err := appendFields(fields, t, nil, "")
+	if err != nil {
+		return fields, err
+	}
+
+	names := make(map[string]bool, len(fields))
+	hasExtra := false
+	for _, fd := range fields {
+		if fd.extra {
+			if hasExtra {
+				return fields, fmt.Errorf("more than one extra field")
+			}
+			hasExtra = true
+			continue
+		}
+		if names[fd.name] {
+			return fields, fmt.Errorf("duplicate column: %s", fd.name)
+		}
+		names[fd.name] = true
 	}
 
+	return fields, nil
+}
+
+// appendFields appends the fields of struct type t to fields, flattening
+// embedded structs and structs with a prefix= option. index is the path to t
+// from the outermost struct, and prefix is prepended to its column names.
+func appendFields(fields []field, t reflect.Type, index []int, prefix string) ([]field, error) {
 	for i := range t.NumField() {
 		f := t.Field(i)
-		if name := getExportedName(f); name != "-" {
-			headers[name] = i
+		fi := append(slices.Clone(index), i)
+		name, opts, err := getExportedName(f)
+		if err != nil {
+			return fields, fmt.Errorf("invalid tag for %s: %w", f.Name, err)
+		}
+
+		if flattened(f, opts) {
+			var err error
+			if fields, err = appendFields(fields, f.Type, fi, prefix+opts.prefix); err != nil {
+				return fields, err
+			}
+			continue
+		}
+
+		if name == "-" {
+			continue
+		}
+		name = prefix + name
+
+		if opts.extra {
+			if f.Type != extraType {
+				return fields, fmt.Errorf("extra field %s must be map[string]string", f.Name)
+			}
+			fields = append(fields, field{name: name, index: fi, tagOptions: opts})
+			continue
+		}
+
+		if err := opts.checkSeparator(f.Type); err != nil {
+			return fields, fmt.Errorf("invalid separator for %s: %w", name, err)
+		}
+
+		// a type that cannot be decoded is only an error when unmarshaling,
+		// which codecFor records
+		dec, err := compileFieldDecoder(f.Type, opts)
+		if err != nil && opts.parser != "" {
+			return fields, fmt.Errorf("invalid parser for %s: %w", name, err)
 		}
+
+		if opts.hasDefault && err == nil {
+			if err := dec(reflect.New(f.Type).Elem(), opts.defaultValue); err != nil {
+				return fields, fmt.Errorf("invalid default for %s: %w", name, err)
+			}
+		}
+
+		fd := field{name: name, index: fi, tagOptions: opts}
+		if opts.hasOrder {
+			if fd.position, err = strconv.Atoi(opts.order); err != nil {
+				return fields, fmt.Errorf("invalid order for %s: %q is not an integer", name, opts.order)
+			}
+		}
+
+		fields = append(fields, fd)
+	}
+
+	return fields, nil
+}
+
+// flattened reports whether the columns of f are those of its own fields:
+// f must be a struct without its own text encoding, and either embedded
+// without a column name or tagged with prefix=.
+func flattened(f reflect.StructField, opts tagOptions) bool {
+	t := f.Type
+	if t.Kind() != reflect.Struct ||
+		reflect.PointerTo(t).Implements(textUnmarshalerType) ||
+		reflect.PointerTo(t).Implements(textMarshalerType) ||
+		hasTypeParser(t) {
+		return false
+	}
+
+	name, _, _ := strings.Cut(f.Tag.Get("csv"), ",")
+	if name == "-" || !(f.IsExported() || f.Anonymous) {
+		return false
 	}
 
-	return headers, nil
+	return opts.hasPrefix || (f.Anonymous && name == "")
+}
+
+// goName returns the path to fd from t, as in Coords.Latitude.
+func (fd field) goName(t reflect.Type) string {
+	names := make([]string, len(fd.index))
+	for k, i := range fd.index {
+		f := t.Field(i)
+		names[k] = f.Name
+		t = f.Type
+	}
+	return strings.Join(names, ".")
 }
 
-func getExportedName(f reflect.StructField) string {
+// getExportedName returns the column name of f, or "-" if it has none, and
+// its tag options. Options are parsed for unexported fields too, since an
+// unexported embedded struct may still be flattened. It returns an error for
+// unknown options.
+func getExportedName(f reflect.StructField) (string, tagOptions, error) {
 	name := "-"
 	if f.IsExported() {
 		name = f.Name
-		if tag, ok := f.Tag.Lookup("csv"); ok {
-			tags := strings.Split(tag, ",")
-			for i, tag := range tags {
-				switch i {
-				case 0:
-					if tag != "" {
-						name = tag
-					}
-				// for future use
-				default:
+	}
+
+	var opts tagOptions
+	if tag, ok := f.Tag.Lookup("csv"); ok {
+		tags := splitTag(tag)
+		for i, tag := range tags {
+			switch {
+			case i == 0:
+				if tag != "" && f.IsExported() {
+					name = tag
 				}
+			case tag == "omitempty":
+				opts.omitEmpty = true
+			case tag == "required":
+				opts.required = true
+			case strings.HasPrefix(tag, "default="):
+				opts.hasDefault = true
+				opts.defaultValue = strings.TrimPrefix(tag, "default=")
+			case tag == "extra":
+				opts.extra = true
+			case strings.HasPrefix(tag, "prefix="):
+				opts.hasPrefix = true
+				opts.prefix = strings.TrimPrefix(tag, "prefix=")
+			case strings.HasPrefix(tag, "order="):
+				opts.hasOrder = true
+				opts.order = strings.TrimPrefix(tag, "order=")
+			case strings.HasPrefix(tag, "sep="):
+				opts.sep = strings.TrimPrefix(tag, "sep=")
+			case strings.HasPrefix(tag, "parse="):
+				opts.parser = strings.TrimPrefix(tag, "parse=")
+			default:
+				return name, opts, fmt.Errorf("unknown option %q", tag)
 			}
 		}
 	}
-	return name
-}
 
-func getOrderedHeaders(hm map[string]int) ([]string, []int) {
-	hh := make([]string, 0, len(hm))
-	fl := make([]int, 0, len(hm))
+	return name, opts, nil
+}
 
-	for n, i := range hm {
-		hh = append(hh, n)
-		fl = append(fl, i)
+// splitTag splits a csv tag into the column name and its options, at commas.
+// Within the options, a doubled comma is a comma in the option value, as in
+// default=a,,b.
+func splitTag(tag string) []string {
+	name, rest, ok := strings.Cut(tag, ",")
+	tags := []string{name}
+	if !ok {
+		return tags
 	}
 
-	sort.SliceStable(hh, func(i, j int) bool {
-		return hm[hh[i]] < hm[hh[j]]
-	})
-	sort.Ints(fl)
-
-	return hh, fl
+	var b strings.Builder
+	for i := 0; i < len(rest); i++ {
+		switch {
+		case rest[i] != ',':
+			b.WriteByte(rest[i])
+		case i+1 < len(rest) && rest[i+1] == ',':
+			b.WriteByte(',')
+			i++
+		default:
+			tags = append(tags, b.String())
+			b.Reset()
+		}
+	}
+	return append(tags, b.String())
 }
@@ -1,35 +1,40 @@
 package csvmum
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"math"
 	"reflect"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
-func TestBuildFieldMap(t *testing.T) {
+func TestBuildFieldList(t *testing.T) {
 	t.Parallel()
 
 	tt := []struct {
 		name     string
 		input    any
-		expected map[string]int
+		expected []field
 		err      error
 	}{{
 		name:     "empty",
 		input:    struct{}{},
-		expected: map[string]int{},
+		expected: []field{},
 		err:      nil,
 	}, {
 		name:     "not a struct",
 		input:    []int{1, 2, 3},
-		expected: map[string]int{},
+		expected: []field{},
 		err:      fmt.Errorf("cannot get headers: not a struct"),
 	}, {
 		name:     "simple",
 		input:    struct{ One string }{},
-		expected: map[string]int{"One": 0},
+		expected: []field{{name: "One", index: []int{0}}},
 		err:      nil,
 	}, {
 		name: "complex",
@@ -38,7 +43,7 @@ func TestBuildFieldMap(t *testing.T) {
 			Two   int
 			Three bool
 		}{},
-		expected: map[string]int{"One": 0, "Two": 1, "Three": 2},
+		expected: []field{{name: "One", index: []int{0}}, {name: "Two", index: []int{1}}, {name: "Three", index: []int{2}}},
 		err:      nil,
 	}, {
 		name: "unexported",
@@ -47,7 +52,7 @@ func TestBuildFieldMap(t *testing.T) {
 			two   int
 			Three bool
 		}{},
-		expected: map[string]int{"One": 0, "Three": 2},
+		expected: []field{{name: "One", index: []int{0}}, {name: "Three", index: []int{2}}},
 		err:      nil,
 	}, {
 		name: "tagged",
@@ -55,7 +60,7 @@ func TestBuildFieldMap(t *testing.T) {
 			One string `csv:"uno"`
 			Two int    `csv:"dos"`
 		}{},
-		expected: map[string]int{"uno": 0, "dos": 1},
+		expected: []field{{name: "uno", index: []int{0}}, {name: "dos", index: []int{1}}},
 		err:      nil,
 	}, {
 		name: "tagged but not exported",
@@ -63,7 +68,7 @@ func TestBuildFieldMap(t *testing.T) {
 			One string `csv:"uno"`
 			two int    `csv:"dos"`
 		}{},
-		expected: map[string]int{"uno": 0},
+		expected: []field{{name: "uno", index: []int{0}}},
 		err:      nil,
 	}, {
 		name: "tagged with hyphen -",
@@ -71,8 +76,94 @@ func TestBuildFieldMap(t *testing.T) {
 			One string `csv:"-"`
 			Two int    `csv:"dos"`
 		}{},
-		expected: map[string]int{"dos": 1},
+		expected: []field{{name: "dos", index: []int{1}}},
 		err:      nil,
+	}, {
+		name: "tagged with options",
+		input: struct {
+			One string `csv:"uno,required"`
+			Two int    `csv:"dos,omitempty,default=2"`
+		}{},
+		expected: []field{
+			{name: "uno", index: []int{0}, tagOptions: tagOptions{required: true}},
+			{name: "dos", index: []int{1}, tagOptions: tagOptions{omitEmpty: true, hasDefault: true, defaultValue: "2"}},
+		},
+		err: nil,
+	}, {
+		name: "embedded",
+		input: struct {
+			fixtureInner
+			Three bool
+		}{},
+		expected: []field{{name: "one", index: []int{0, 0}}, {name: "Two", index: []int{0, 1}}, {name: "Three", index: []int{1}}},
+		err:      nil,
+	}, {
+		name: "embedded with prefix",
+		input: struct {
+			fixtureInner `csv:",prefix=in_"`
+		}{},
+		expected: []field{{name: "in_one", index: []int{0, 0}}, {name: "in_Two", index: []int{0, 1}}},
+		err:      nil,
+	}, {
+		name: "embedded with name",
+		input: struct {
+			fixtureInner `csv:"inner"`
+		}{},
+		expected: []field{},
+		err:      nil,
+	}, {
+		name: "named with prefix",
+		input: struct {
+			Zero  string
+			Inner fixtureInner `csv:",prefix=in_"`
+			Outer struct {
+				Inner fixtureInner `csv:",prefix=in_"`
+			} `csv:",prefix=out_"`
+		}{},
+		expected: []field{
+			{name: "Zero", index: []int{0}},
+			{name: "in_one", index: []int{1, 0}},
+			{name: "in_Two", index: []int{1, 1}},
+			{name: "out_in_one", index: []int{2, 0, 0}},
+			{name: "out_in_Two", index: []int{2, 0, 1}},
+		},
+		err: nil,
+	}, {
+		name: "named without prefix",
+		input: struct {
+			Inner fixtureInner
+		}{},
+		expected: []field{{name: "Inner", index: []int{0}}},
+		err:      nil,
+	}, {
+		name: "embedded text type",
+		input: struct {
+			customMarshalAndUnmarshal
+		}{},
+		expected: []field{},
+		err:      nil,
+	}, {
+		name: "duplicate column",
+		input: struct {
+			fixtureInner
+			One string `csv:"one"`
+		}{},
+		expected: []field{{name: "one", index: []int{0, 0}}, {name: "Two", index: []int{0, 1}}, {name: "one", index: []int{1}}},
+		err:      fmt.Errorf("duplicate column: one"),
+	}, {
+		name: "invalid default",
+		input: struct {
+			One int `csv:"uno,default=one"`
+		}{},
+		expected: []field{},
+		err:      fmt.Errorf("invalid default for uno: error parsing int: strconv.ParseInt: parsing \"one\": invalid syntax"),
+	}, {
+		name: "unknown option",
+		input: struct {
+			One string `csv:"uno,requird"`
+		}{},
+		expected: []field{},
+		err:      fmt.Errorf("invalid tag for One: unknown option \"requird\""),
 	}}
 
 	for _, tc := range tt {
@@ -82,9 +173,13 @@ func TestBuildFieldMap(t *testing.T) {
 
 			assert := assert.New(t)
 
-			headers, err := buildFieldMap(reflect.TypeOf(tc.input))
-			assert.Equal(tc.expected, headers)
-			assert.Equal(tc.err, err)
+			fields, err := buildFieldList(reflect.TypeOf(tc.input))
+			assert.Equal(tc.expected, fields)
+			if tc.err != nil {
+				assert.EqualError(err, tc.err.Error())
+			} else {
+				assert.Nil(err)
+			}
 		})
 	}
 }
@@ -96,6 +191,8 @@ func TestGetExportedName(t *testing.T) {
 		name     string
 		input    any
 		expected string
+		options  tagOptions
+		err      string
 	}{{
 		name:     "unexported",
 		input:    struct{ one string }{},
@@ -111,11 +208,19 @@ func TestGetExportedName(t *testing.T) {
 		}{},
 		expected: "uno",
 	}, {
-		name: "tagged multiple",
+		name: "unknown option",
 		input: struct {
-			One string `csv:"uno,dos"`
+			One string `csv:"uno,requird"`
 		}{},
 		expected: "uno",
+		err:      `unknown option "requird"`,
+	}, {
+		name: "empty option",
+		input: struct {
+			One string `csv:"uno,omitempty,"`
+		}{},
+		expected: "uno",
+		err:      `unknown option ""`,
 	}, {
 		name: "tagged empty",
 		input: struct {
@@ -128,6 +233,90 @@ func TestGetExportedName(t *testing.T) {
 			One string `csv:"-"`
 		}{},
 		expected: "-",
+	}, {
+		name: "omitempty",
+		input: struct {
+			One string `csv:"uno,omitempty"`
+		}{},
+		expected: "uno",
+		options:  tagOptions{omitEmpty: true},
+	}, {
+		name: "required",
+		input: struct {
+			One string `csv:"uno,required"`
+		}{},
+		expected: "uno",
+		options:  tagOptions{required: true},
+	}, {
+		name: "default",
+		input: struct {
+			One string `csv:",default=uno"`
+		}{},
+		expected: "One",
+		options:  tagOptions{hasDefault: true, defaultValue: "uno"},
+	}, {
+		name: "default with commas",
+		input: struct {
+			One string `csv:"uno,default=a,,b,,,omitempty"`
+		}{},
+		expected: "uno",
+		options:  tagOptions{hasDefault: true, defaultValue: "a,b,", omitEmpty: true},
+	}, {
+		name: "empty default",
+		input: struct {
+			One string `csv:"uno,default="`
+		}{},
+		expected: "uno",
+		options:  tagOptions{hasDefault: true},
+	}, {
+		name: "parse",
+		input: struct {
+			One string `csv:"uno,parse=color"`
+		}{},
+		expected: "uno",
+		options:  tagOptions{parser: "color"},
+	}, {
+		name: "extra",
+		input: struct {
+			One map[string]string `csv:",extra"`
+		}{},
+		expected: "One",
+		options:  tagOptions{extra: true},
+	}, {
+		name: "prefix",
+		input: struct {
+			One struct{} `csv:",prefix=uno_"`
+		}{},
+		expected: "One",
+		options:  tagOptions{hasPrefix: true, prefix: "uno_"},
+	}, {
+		name: "sep",
+		input: struct {
+			One []string `csv:",sep=;"`
+		}{},
+		expected: "One",
+		options:  tagOptions{sep: ";"},
+	}, {
+		name: "order",
+		input: struct {
+			One string `csv:"uno,order=2"`
+		}{},
+		expected: "uno",
+		options:  tagOptions{hasOrder: true, order: "2"},
+	}, {
+		name: "unexported options",
+		input: struct {
+			one struct{} `csv:"uno,prefix=uno_"`
+		}{},
+		expected: "-",
+		options:  tagOptions{hasPrefix: true, prefix: "uno_"},
+	}, {
+		name: "all options",
+		input: struct {
+			One string `csv:"uno,required,omitempty,default=dos"`
+		}{},
+		expected: "uno",
+		options:  tagOptions{omitEmpty: true, required: true, hasDefault: true, defaultValue: "dos"},
 	}}
 
 	for _, tc := range tt {
@@ -139,51 +328,150 @@ func TestGetExportedName(t *testing.T) {
 
 			f := reflect.TypeOf(tc.input).Field(0)
 
-			name := getExportedName(f)
+			name, opts, err := getExportedName(f)
 			assert.Equal(tc.expected, name)
+			if tc.err != "" {
+				assert.EqualError(err, tc.err)
+				return
+			}
+			assert.Nil(err)
+			assert.Equal(tc.options, opts)
 		})
 	}
 }
 
-func TestGetOrderedHeaders(t *testing.T) {
+func TestRoundTrip(t *testing.T) {
 	t.Parallel()
+	assert := assert.New(t)
 
-	tt := []struct {
-		name          string
-		input         map[string]int
-		expHeaderList []string
-		expFieldList  []int
-	}{{
-		name:          "empty",
-		input:         map[string]int{},
-		expHeaderList: []string{},
-		expFieldList:  []int{},
-	}, {
-		name:          "simple",
-		input:         map[string]int{"One": 0},
-		expHeaderList: []string{"One"},
-		expFieldList:  []int{0},
-	}, {
-		name:          "complex",
-		input:         map[string]int{"One": 0, "Two": 1, "Three": 2},
-		expHeaderList: []string{"One", "Two", "Three"},
-		expFieldList:  []int{0, 1, 2},
-	}, {
-		name:          "unordered",
-		input:         map[string]int{"Two": 2, "One": 0, "Three": 7},
-		expHeaderList: []string{"One", "Two", "Three"},
-		expFieldList:  []int{0, 2, 7},
+	type testType struct {
+		String   string
+		Int      int
+		Int8     int8
+		Int16    int16
+		Int32    int32
+		Int64    int64
+		Uint     uint
+		Uint8    uint8
+		Uint16   uint16
+		Uint32   uint32
+		Uint64   uint64
+		Uintptr  uintptr
+		Bool     bool
+		Float32  float32
+		Float64  float64
+		Duration time.Duration
+		Custom   customMarshalAndUnmarshal
+		PString  *string
+		PInt     *int
+		PUint64  *uint64
+		PBool    *bool
+		PFloat   *float64
+		PCustom  *customMarshalAndUnmarshal
+	}
+
+	s, i, u, bl, f := "five", 5, uint64(math.MaxUint64), true, math.SmallestNonzeroFloat64
+
+	in := []testType{{
+		String:   "one",
+		Int:      math.MinInt,
+		Int8:     math.MaxInt8,
+		Int16:    math.MinInt16,
+		Int32:    math.MaxInt32,
+		Int64:    math.MinInt64,
+		Uint:     math.MaxUint,
+		Uint8:    math.MaxUint8,
+		Uint16:   math.MaxUint16,
+		Uint32:   math.MaxUint32,
+		Uint64:   math.MaxUint64,
+		Uintptr:  42,
+		Bool:     true,
+		Float32:  math.MaxFloat32,
+		Float64:  -math.MaxFloat64,
+		Duration: -(time.Hour + time.Nanosecond),
+		Custom:   customMarshalAndUnmarshal{One: "two"},
+		PString:  &s,
+		PInt:     &i,
+		PUint64:  &u,
+		PBool:    &bl,
+		PFloat:   &f,
+		PCustom:  &customMarshalAndUnmarshal{One: "three"},
+	}, {
+		Custom: customMarshalAndUnmarshal{One: "four"},
 	}}
 
-	for _, tc := range tt {
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			t.Parallel()
-			assert := assert.New(t)
+	b := &bytes.Buffer{}
+	m, err := NewMarshaler[testType](b)
+	assert.Nil(err)
 
-			hl, fl := getOrderedHeaders(tc.input)
-			assert.Equal(tc.expHeaderList, hl)
-			assert.Equal(tc.expFieldList, fl)
-		})
+	for _, r := range in {
+		assert.Nil(m.Marshal(r))
 	}
+	assert.Nil(m.Flush())
+
+	um, err := NewUnmarshaler[testType](b)
+	assert.Nil(err)
+
+	out := []testType{}
+	for {
+		var r testType
+		err := um.Unmarshal(&r)
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(err)
+		out = append(out, r)
+	}
+
+	assert.Equal(in, out)
+}
+
+func TestNested(t *testing.T) {
+	t.Parallel()
+
+	type latLon struct {
+		Lat *float64 `csv:"lat"`
+		Lon *float64 `csv:"lon"`
+	}
+
+	type testType struct {
+		fixtureInner
+		Coords latLon `csv:",prefix=stop_"`
+		Name   string `csv:"name"`
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		lat, lon := 34.05, -118.25
+		in := []testType{
+			{fixtureInner: fixtureInner{One: "one", Two: 2}, Coords: latLon{Lat: &lat, Lon: &lon}, Name: "Main St"},
+			{Name: "Broadway"},
+		}
+
+		b := &bytes.Buffer{}
+		m, err := NewMarshaler[testType](b)
+		assert.NoError(err)
+		assert.NoError(m.WriteAll(slices.Values(in)))
+		assert.Equal("one,Two,stop_lat,stop_lon,name\none,2,34.05,-118.25,Main St\n,0,,,Broadway\n", b.String())
+
+		um, err := NewUnmarshaler[testType](b)
+		assert.NoError(err)
+
+		out, err := um.ReadAll()
+		assert.NoError(err)
+		assert.Equal(in, out)
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		um, err := NewUnmarshaler[testType](bytes.NewBufferString("name,stop_lat\nMain St,north\n"))
+		assert.NoError(err)
+
+		var r testType
+		assert.EqualError(um.Unmarshal(&r), `cannot unmarshal line 2, column "stop_lat", field Coords.Lat, value "north": error parsing float64: strconv.ParseFloat: parsing "north": invalid syntax`)
+	})
 }
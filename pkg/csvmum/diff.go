@@ -0,0 +1,468 @@
+package csvmum
+
+import (
+	"cmp"
+	"container/heap"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// defaultSortBuffer is the number of records of each input Diff sorts in
+// memory without WithSortBuffer.
+const defaultSortBuffer = 100_000
+
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota + 1
+	Removed
+	Modified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is a difference between the records with the same key in two inputs.
+type Change[T any] struct {
+	Kind ChangeKind
+	Key  string
+	// Old is the zero value for added records, and New for removed ones.
+	// Both are rebuilt from their columns, with extra columns, so they are
+	// as read: defaults are not applied, AfterUnmarshalCSV is not called
+	// again, and unexported fields are not kept.
+	Old T
+	New T
+	// Columns holds the columns that differ, for modified records.
+	Columns []ColumnChange
+}
+
+// ColumnChange is a column that differs between two versions of a record.
+// Values are as a marshaler would write them, ignoring omitempty, so that
+// formatting differences in the inputs, such as 1.50 and 1.5, are not changes.
+type ColumnChange struct {
+	Column string
+	Old    string
+	New    string
+}
+
+// Diff compares the records of T in old and new, matching them by key, and
+// returns an iterator over the records added, removed and modified, in key
+// order. Extra columns are not compared.
+//
+// Each input is sorted by key, in memory if it has no more records than the
+// sort buffer (see WithSortBuffer), and otherwise by merging sorted runs
+// written to temporary files, so inputs of any size can be compared.
+//
+// Errors reading either input, and duplicate keys, are yielded with a zero
+// Change. They stop the iterator unless ContinueOnError is given, in which
+// case the records in error, and all but the first record with each key, are
+// left out.
+func Diff[T any](old, new io.Reader, key func(T) string, opts ...Option) iter.Seq2[Change[T], error] {
+	return func(yield func(Change[T], error) bool) {
+		o := buildOptions(opts)
+		t := reflect.TypeFor[T]()
+
+		c, err := codecFor(t)
+		if err != nil {
+			yield(Change[T]{}, fmt.Errorf("cannot diff: %w", err))
+			return
+		}
+		if err := cmp.Or(c.decodeErr, c.encodeErr); err != nil {
+			yield(Change[T]{}, fmt.Errorf("cannot diff: %w", err))
+			return
+		}
+
+		d := &differ[T]{codec: c, key: key, opts: o, options: opts, yield: yield}
+
+		oldRows := d.newSorter()
+		defer oldRows.close()
+		if !d.read(old, "old", oldRows) {
+			return
+		}
+
+		newRows := d.newSorter()
+		defer newRows.close()
+		if !d.read(new, "new", newRows) {
+			return
+		}
+
+		d.merge(oldRows, newRows)
+	}
+}
+
+type differ[T any] struct {
+	codec   *codec
+	key     func(T) string
+	opts    options
+	options []Option
+	yield   func(Change[T], error) bool
+}
+
+// fail yields err, and reports whether the diff should go on.
+func (d *differ[T]) fail(err error) bool {
+	return d.yield(Change[T]{}, err) && d.opts.continueOnError
+}
+
+func (d *differ[T]) newSorter() *rowSorter {
+	limit := d.opts.sortBuffer
+	if limit <= 0 {
+		limit = defaultSortBuffer
+	}
+	return &rowSorter{limit: limit}
+}
+
+// read adds the records of r to s, keyed and encoded.
+func (d *differ[T]) read(r io.Reader, name string, s *rowSorter) bool {
+	um, err := NewUnmarshaler[T](r, d.options...)
+	if err == io.EOF {
+		return true
+	}
+	if err != nil {
+		d.yield(Change[T]{}, fmt.Errorf("cannot diff %s input: %w", name, err))
+		return false
+	}
+
+	for record, err := range um.All() {
+		if err != nil {
+			if !d.fail(fmt.Errorf("cannot diff %s input: %w", name, err)) {
+				return false
+			}
+			continue
+		}
+
+		row, err := d.encode(&record)
+		if err == nil {
+			err = s.add(row)
+		}
+		if err != nil {
+			d.yield(Change[T]{}, fmt.Errorf("cannot diff %s input: %w", name, err))
+			return false
+		}
+	}
+
+	return true
+}
+
+// encode returns the keyed row for record, with its columns encoded without
+// omitempty and its extra columns in name order.
+func (d *differ[T]) encode(record *T) (keyedRow, error) {
+	v := reflect.ValueOf(record).Elem()
+	r := keyedRow{key: d.key(*record), cells: make([]string, len(d.codec.fields))}
+	for i, fd := range d.codec.fields {
+		s, err := d.codec.rawEncoders[i](v.FieldByIndex(fd.index))
+		if err != nil {
+			return r, err
+		}
+		r.cells[i] = s
+	}
+
+	if d.codec.extra != nil {
+		extra := v.FieldByIndex(d.codec.extra).Interface().(map[string]string)
+		for _, k := range slices.Sorted(maps.Keys(extra)) {
+			r.extra = append(r.extra, k, extra[k])
+		}
+	}
+	return r, nil
+}
+
+// decode rebuilds the record of r, without applying defaults or calling
+// AfterUnmarshalCSV.
+func (d *differ[T]) decode(r keyedRow) (T, error) {
+	var record T
+	v := reflect.ValueOf(&record).Elem()
+	for i, fd := range d.codec.fields {
+		if err := d.codec.rawDecoders[i](v.FieldByIndex(fd.index), r.cells[i]); err != nil {
+			return record, fmt.Errorf("field %s: %w", fd.name, err)
+		}
+	}
+
+	if len(r.extra) > 0 {
+		extra := make(map[string]string, len(r.extra)/2)
+		for i := 0; i < len(r.extra); i += 2 {
+			extra[r.extra[i]] = r.extra[i+1]
+		}
+		v.FieldByIndex(d.codec.extra).Set(reflect.ValueOf(extra))
+	}
+	return record, nil
+}
+
+// merge joins the sorted rows of old and new, yielding their differences.
+func (d *differ[T]) merge(old, new *rowSorter) {
+	oldRows, err := old.sorted()
+	if err != nil {
+		d.yield(Change[T]{}, fmt.Errorf("cannot diff old input: %w", err))
+		return
+	}
+	newRows, err := new.sorted()
+	if err != nil {
+		d.yield(Change[T]{}, fmt.Errorf("cannot diff new input: %w", err))
+		return
+	}
+
+	o, oOK, ok := d.next(oldRows, "old", nil)
+	if !ok {
+		return
+	}
+	n, nOK, ok := d.next(newRows, "new", nil)
+	if !ok {
+		return
+	}
+
+	for oOK || nOK {
+		var change Change[T]
+		var err error
+		advanceOld, advanceNew := true, true
+
+		switch {
+		case !nOK || (oOK && o.key < n.key):
+			change = Change[T]{Kind: Removed, Key: o.key}
+			change.Old, err = d.decode(o)
+			advanceNew = false
+		case !oOK || n.key < o.key:
+			change = Change[T]{Kind: Added, Key: n.key}
+			change.New, err = d.decode(n)
+			advanceOld = false
+		default:
+			change = Change[T]{Kind: Modified, Key: o.key}
+			for i, fd := range d.codec.fields {
+				if o.cells[i] != n.cells[i] {
+					change.Columns = append(change.Columns, ColumnChange{Column: fd.name, Old: o.cells[i], New: n.cells[i]})
+				}
+			}
+			if len(change.Columns) > 0 {
+				if change.Old, err = d.decode(o); err == nil {
+					change.New, err = d.decode(n)
+				}
+			}
+		}
+
+		if err != nil {
+			d.yield(Change[T]{}, fmt.Errorf("cannot diff: %w", err))
+			return
+		}
+		if (change.Kind != Modified || len(change.Columns) > 0) && !d.yield(change, nil) {
+			return
+		}
+
+		if advanceOld {
+			if o, oOK, ok = d.next(oldRows, "old", &o); !ok {
+				return
+			}
+		}
+		if advanceNew {
+			if n, nOK, ok = d.next(newRows, "new", &n); !ok {
+				return
+			}
+		}
+	}
+}
+
+// next returns the row after prev, skipping rows with the same key. The last
+// result reports whether the diff should go on.
+func (d *differ[T]) next(rows rowIterator, name string, prev *keyedRow) (keyedRow, bool, bool) {
+	for {
+		r, ok, err := rows.next()
+		if err != nil {
+			d.yield(Change[T]{}, fmt.Errorf("cannot diff %s input: %w", name, err))
+			return r, false, false
+		}
+		if !ok || prev == nil || r.key != prev.key {
+			return r, ok, true
+		}
+		if !d.fail(fmt.Errorf("cannot diff %s input: duplicate key %q", name, r.key)) {
+			return r, false, false
+		}
+	}
+}
+
+type keyedRow struct {
+	key   string
+	cells []string
+	// extra holds the names and values of extra columns, in pairs
+	extra []string
+}
+
+type rowIterator interface {
+	next() (keyedRow, bool, error)
+}
+
+// rowSorter sorts rows by key. Once it holds limit rows, they are sorted and
+// written to a temporary file as a run, and the runs are merged at the end.
+type rowSorter struct {
+	limit int
+	rows  []keyedRow
+	runs  []*os.File
+}
+
+func (s *rowSorter) add(r keyedRow) error {
+	s.rows = append(s.rows, r)
+	if len(s.rows) < s.limit {
+		return nil
+	}
+	return s.spill()
+}
+
+func (s *rowSorter) sort() {
+	// stable, so that the first of several rows with a key is kept
+	slices.SortStableFunc(s.rows, func(a, b keyedRow) int {
+		return cmp.Compare(a.key, b.key)
+	})
+}
+
+func (s *rowSorter) spill() error {
+	s.sort()
+
+	f, err := os.CreateTemp("", "csvmum-diff-*.csv")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, f)
+
+	w := csv.NewWriter(f)
+	for _, r := range s.rows {
+		// the number of cells comes first, so that the extra columns after
+		// them can be told apart
+		rec := append([]string{r.key, strconv.Itoa(len(r.cells))}, r.cells...)
+		if err := w.Write(append(rec, r.extra...)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+
+	s.rows = s.rows[:0]
+	_, err = f.Seek(0, io.SeekStart)
+	return err
+}
+
+// sorted returns the rows in key order.
+func (s *rowSorter) sorted() (rowIterator, error) {
+	if len(s.runs) == 0 {
+		s.sort()
+		return &sliceRows{rows: s.rows}, nil
+	}
+
+	if len(s.rows) > 0 {
+		if err := s.spill(); err != nil {
+			return nil, err
+		}
+	}
+
+	m := &mergedRows{}
+	for i, f := range s.runs {
+		cr := csv.NewReader(f)
+		cr.FieldsPerRecord = -1
+		r := &runReader{reader: cr, run: i}
+		if err := m.push(r); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (s *rowSorter) close() {
+	for _, f := range s.runs {
+		f.Close()
+		os.Remove(f.Name())
+	}
+}
+
+type sliceRows struct {
+	rows []keyedRow
+}
+
+func (s *sliceRows) next() (keyedRow, bool, error) {
+	if len(s.rows) == 0 {
+		return keyedRow{}, false, nil
+	}
+	r := s.rows[0]
+	s.rows = s.rows[1:]
+	return r, true, nil
+}
+
+// runReader reads a run, holding its next row.
+type runReader struct {
+	reader *csv.Reader
+	run    int
+	row    keyedRow
+}
+
+func (r *runReader) read() (bool, error) {
+	rec, err := r.reader.Read()
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	n, err := strconv.Atoi(rec[1])
+	if err != nil {
+		return false, err
+	}
+	r.row = keyedRow{key: rec[0], cells: rec[2 : 2+n], extra: rec[2+n:]}
+	return true, nil
+}
+
+// mergedRows merges runs with a heap of their next rows. Rows with equal keys
+// come from earlier runs first.
+type mergedRows struct {
+	runs runHeap
+}
+
+func (m *mergedRows) push(r *runReader) error {
+	ok, err := r.read()
+	if ok {
+		heap.Push(&m.runs, r)
+	}
+	return err
+}
+
+func (m *mergedRows) next() (keyedRow, bool, error) {
+	if len(m.runs) == 0 {
+		return keyedRow{}, false, nil
+	}
+
+	r := heap.Pop(&m.runs).(*runReader)
+	row := r.row
+	return row, true, m.push(r)
+}
+
+type runHeap []*runReader
+
+func (h runHeap) Len() int { return len(h) }
+
+func (h runHeap) Less(i, j int) bool {
+	if h[i].row.key != h[j].row.key {
+		return h[i].row.key < h[j].row.key
+	}
+	return h[i].run < h[j].run
+}
+
+func (h runHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *runHeap) Push(x any) { *h = append(*h, x.(*runReader)) }
+
+func (h *runHeap) Pop() any {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
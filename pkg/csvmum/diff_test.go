@@ -0,0 +1,229 @@
+package csvmum
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type diffStop struct {
+	ID   string   `csv:"stop_id"`
+	Name string   `csv:"stop_name"`
+	Lat  *float64 `csv:"stop_lat"`
+}
+
+func diffStopKey(s diffStop) string {
+	return s.ID
+}
+
+func collectDiff[T any](old, new string, key func(T) string, opts ...Option) ([]Change[T], []string) {
+	var changes []Change[T]
+	var errs []string
+	for c, err := range Diff(strings.NewReader(old), strings.NewReader(new), key, opts...) {
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		changes = append(changes, c)
+	}
+	return changes, errs
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	old := "stop_id,stop_name,stop_lat\n" +
+		"S3,Sunset,34.10\n" +
+		"S1,Main St,34.05\n" +
+		"S2,Broadway,34.06\n" +
+		"S4,Vermont,\n"
+	new := "stop_lat,stop_id,stop_name\n" +
+		"34.050,S1,Main St\n" +
+		"34.07,S2,Broadway Station\n" +
+		",S5,Western\n" +
+		"34.1,S3,Sunset\n"
+
+	lat := func(f float64) *float64 { return &f }
+	expected := []Change[diffStop]{{
+		Kind: Modified,
+		Key:  "S2",
+		Old:  diffStop{ID: "S2", Name: "Broadway", Lat: lat(34.06)},
+		New:  diffStop{ID: "S2", Name: "Broadway Station", Lat: lat(34.07)},
+		Columns: []ColumnChange{
+			{Column: "stop_name", Old: "Broadway", New: "Broadway Station"},
+			{Column: "stop_lat", Old: "34.06", New: "34.07"},
+		},
+	}, {
+		Kind: Removed,
+		Key:  "S4",
+		Old:  diffStop{ID: "S4", Name: "Vermont"},
+	}, {
+		Kind: Added,
+		Key:  "S5",
+		New:  diffStop{ID: "S5", Name: "Western"},
+	}}
+
+	t.Run("in memory", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		changes, errs := collectDiff(old, new, diffStopKey)
+		assert.Empty(errs)
+		assert.Equal(expected, changes)
+	})
+
+	t.Run("external sort", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		changes, errs := collectDiff(old, new, diffStopKey, WithSortBuffer(1))
+		assert.Empty(errs)
+		assert.Equal(expected, changes)
+	})
+
+	t.Run("empty inputs", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		changes, errs := collectDiff("", "stop_id,stop_name\nS1,Main St\n", diffStopKey)
+		assert.Empty(errs)
+		assert.Equal([]Change[diffStop]{{Kind: Added, Key: "S1", New: diffStop{ID: "S1", Name: "Main St"}}}, changes)
+
+		changes, errs = collectDiff("", "", diffStopKey)
+		assert.Empty(errs)
+		assert.Empty(changes)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		old := "stop_id,stop_lat\nS1,north\nS2,1\nS2,2\nS3,3\n"
+		new := "stop_id,stop_lat\nS3,4\n"
+
+		changes, errs := collectDiff(old, new, diffStopKey)
+		assert.Equal([]string{
+			`cannot diff old input: cannot unmarshal line 2, column "stop_lat", field Lat, value "north": error parsing float64: strconv.ParseFloat: parsing "north": invalid syntax`,
+		}, errs)
+		assert.Empty(changes)
+
+		for _, buffer := range []int{1, 100} {
+			changes, errs = collectDiff(old, new, diffStopKey, ContinueOnError(), WithSortBuffer(buffer))
+			assert.Equal([]string{
+				`cannot diff old input: cannot unmarshal line 2, column "stop_lat", field Lat, value "north": error parsing float64: strconv.ParseFloat: parsing "north": invalid syntax`,
+				`cannot diff old input: duplicate key "S2"`,
+			}, errs)
+			assert.Equal([]Change[diffStop]{
+				{Kind: Removed, Key: "S2", Old: diffStop{ID: "S2", Lat: lat(1)}},
+				{Kind: Modified, Key: "S3", Old: diffStop{ID: "S3", Lat: lat(3)}, New: diffStop{ID: "S3", Lat: lat(4)},
+					Columns: []ColumnChange{{Column: "stop_lat", Old: "3", New: "4"}}},
+			}, changes, "sort buffer %d", buffer)
+		}
+	})
+
+	t.Run("record values", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type stop struct {
+			ID     string            `csv:"stop_id"`
+			Name   string            `csv:"stop_name"`
+			Routes int               `csv:"routes,default=5,omitempty"`
+			Extra  map[string]string `csv:",extra"`
+		}
+		key := func(s stop) string { return s.ID }
+
+		old := "stop_id,stop_name,routes,zone\nS1,Main St,0,A\n"
+		new := "stop_id,stop_name,routes,zone\nS1,Main Street,0,B\n"
+
+		for _, buffer := range []int{1, 100} {
+			changes, errs := collectDiff(old, new, key, WithSortBuffer(buffer))
+			assert.Empty(errs)
+			assert.Equal([]Change[stop]{{
+				Kind:    Modified,
+				Key:     "S1",
+				Old:     stop{ID: "S1", Name: "Main St", Extra: map[string]string{"zone": "A"}},
+				New:     stop{ID: "S1", Name: "Main Street", Extra: map[string]string{"zone": "B"}},
+				Columns: []ColumnChange{{Column: "stop_name", Old: "Main St", New: "Main Street"}},
+			}}, changes, "sort buffer %d", buffer)
+		}
+	})
+
+	t.Run("break", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		n := 0
+		for range Diff(strings.NewReader(old), strings.NewReader(new), diffStopKey, WithSortBuffer(1)) {
+			n++
+			break
+		}
+		assert.Equal(1, n)
+	})
+}
+
+func TestRowSorter(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s := &rowSorter{limit: 3}
+	for i := range 10 {
+		k := fmt.Sprint((i * 7) % 10)
+		assert.NoError(s.add(keyedRow{key: k, cells: []string{k, "a,\"b\"\nc"}}))
+	}
+	assert.Len(s.runs, 3)
+
+	names := make([]string, len(s.runs))
+	for i, f := range s.runs {
+		names[i] = f.Name()
+	}
+
+	rows, err := s.sorted()
+	assert.NoError(err)
+
+	var keys []string
+	for {
+		r, ok, err := rows.next()
+		assert.NoError(err)
+		if !ok {
+			break
+		}
+		assert.Equal([]string{r.key, "a,\"b\"\nc"}, r.cells)
+		keys = append(keys, r.key)
+	}
+	assert.Equal([]string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, keys)
+
+	s.close()
+	for _, name := range names {
+		_, err := os.Stat(name)
+		assert.True(os.IsNotExist(err), filepath.Base(name))
+	}
+}
+
+func BenchmarkDiff(b *testing.B) {
+	old := &bytes.Buffer{}
+	new := &bytes.Buffer{}
+	old.WriteString("stop_id,stop_name,stop_lat\n")
+	new.WriteString("stop_id,stop_name,stop_lat\n")
+	for i := range 10000 {
+		fmt.Fprintf(old, "S%d,Stop %d,%d.5\n", i, i, i)
+		fmt.Fprintf(new, "S%d,Stop %d,%d.5\n", i+i%2*10000, i, i%3)
+	}
+
+	for _, buffer := range []int{100000, 1000} {
+		b.Run(fmt.Sprintf("buffer %d", buffer), func(b *testing.B) {
+			for range b.N {
+				for _, err := range Diff(bytes.NewReader(old.Bytes()), bytes.NewReader(new.Bytes()), diffStopKey, WithSortBuffer(buffer)) {
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package csvmum
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// DynamicReader reads rows of a CSV file whose columns are only known once
+// its header has been read. The same options apply as to CSVUnmarshaler.
+type DynamicReader struct {
+	reader *csv.Reader
+	header *dynamicHeader
+}
+
+// dynamicHeader is the header shared by the rows of a DynamicReader.
+type dynamicHeader struct {
+	names       []string
+	exact       map[string]int
+	diagnostics []HeaderDiagnostic
+	opts        options
+
+	// bindings caches a *rowBinding for each type rows are decoded into
+	bindings sync.Map
+}
+
+type rowBinding struct {
+	binding
+	generated bool
+	err       error
+}
+
+func NewDynamicReader(r io.Reader, opts ...Option) (*DynamicReader, error) {
+	r, _ = buildOptions(opts).stripBOMReader(r)
+	return NewCSVDynamicReader(csv.NewReader(r), opts...)
+}
+
+// NewCSVDynamicReader reads rows from r. Since rows outlive the next call to
+// Read, records are copied if r has ReuseRecord set.
+func NewCSVDynamicReader(r *csv.Reader, opts ...Option) (*DynamicReader, error) {
+	o := buildOptions(opts)
+	dr := &DynamicReader{reader: r}
+
+	if err := o.configureReader(r); err != nil {
+		return dr, fmt.Errorf("cannot unmarshal: %w", err)
+	}
+
+	hh, err := dr.reader.Read()
+	if err == io.EOF {
+		return dr, err
+	}
+	if err != nil {
+		return dr, fmt.Errorf("cannot unmarshal: %w", err)
+	}
+	hh = slices.Clone(hh)
+	o.normalizeHeaderRecord(hh)
+
+	dr.header = newDynamicHeader(hh, o)
+
+	if o.headerPolicy == HeaderStrict && len(dr.header.diagnostics) > 0 {
+		return dr, fmt.Errorf("cannot unmarshal: %w", &HeaderError{Diagnostics: dr.header.diagnostics})
+	}
+
+	return dr, nil
+}
+
+// newDynamicHeader indexes hh by exact column name, whatever the policy. With
+// HeaderWarn or HeaderStrict, duplicate columns are diagnosed; the first of
+// each is used.
+func newDynamicHeader(hh []string, o options) *dynamicHeader {
+	h := &dynamicHeader{names: hh, exact: make(map[string]int, len(hh)), opts: o}
+
+	for i, name := range hh {
+		if _, ok := h.exact[name]; ok {
+			if o.headerPolicy != HeaderLenient {
+				h.diagnostics = append(h.diagnostics, HeaderDiagnostic{Kind: DuplicateColumn, Column: i + 1, Header: name, Name: name})
+			}
+			continue
+		}
+		h.exact[name] = i
+	}
+
+	return h
+}
+
+func (h *dynamicHeader) index(name string) (int, bool) {
+	i, ok := h.exact[name]
+	return i, ok
+}
+
+// Header returns the column names of the header.
+func (dr *DynamicReader) Header() []string {
+	return slices.Clone(dr.header.names)
+}
+
+// Diagnostics returns the problems found in the header when the reader was
+// created. It is always empty with HeaderLenient.
+func (dr *DynamicReader) Diagnostics() []HeaderDiagnostic {
+	return dr.header.diagnostics
+}
+
+// Read reads the next row. It returns io.EOF at the end of the input.
+func (dr *DynamicReader) Read() (Row, error) {
+	r, err := dr.reader.Read()
+	if err == io.EOF {
+		return Row{}, err
+	}
+	if err != nil {
+		return Row{}, fmt.Errorf("cannot unmarshal: %w", err)
+	}
+	if dr.reader.ReuseRecord {
+		r = slices.Clone(r)
+	}
+	dr.header.opts.normalizeRecord(r)
+
+	line, _ := dr.reader.FieldPos(0)
+	return Row{header: dr.header, cells: r, line: line}, nil
+}
+
+// All returns an iterator over the remaining rows, which stops as
+// CSVUnmarshaler.All does.
+func (dr *DynamicReader) All() iter.Seq2[Row, error] {
+	return func(yield func(Row, error) bool) {
+		for {
+			r, err := dr.Read()
+			if err == io.EOF {
+				return
+			}
+			if !yield(r, err) {
+				return
+			}
+			if err != nil && !(dr.header.opts.continueOnError && isRecordError(err)) {
+				return
+			}
+		}
+	}
+}
+
+// ReadAll reads the remaining rows, as CSVUnmarshaler.ReadAll does.
+func (dr *DynamicReader) ReadAll() ([]Row, error) {
+	rows := []Row{}
+	var errs []error
+
+	for r, err := range dr.All() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rows = append(rows, r)
+	}
+
+	return rows, errors.Join(errs...)
+}
+
+// Row is a record read by a DynamicReader. Columns are looked up by name as
+// the reader's header policy allows.
+type Row struct {
+	header *dynamicHeader
+	cells  []string
+	line   int
+}
+
+// Line returns the line the row starts on.
+func (r Row) Line() int {
+	return r.line
+}
+
+// Header returns the column names of the row's header.
+func (r Row) Header() []string {
+	return slices.Clone(r.header.names)
+}
+
+// Values returns the cells of the row, in header order.
+func (r Row) Values() []string {
+	return slices.Clone(r.cells)
+}
+
+// Has reports whether the header has the named column.
+func (r Row) Has(name string) bool {
+	_, ok := r.header.index(name)
+	return ok
+}
+
+// String returns the cell in the named column, or "" if there is no such
+// column.
+func (r Row) String(name string) string {
+	s, _ := r.lookup(name)
+	return s
+}
+
+// Int parses the cell in the named column as an int.
+func (r Row) Int(name string) (int, error) {
+	s, i := r.lookup(name)
+	if i == -1 {
+		return 0, r.parseError(name, i, ErrMissingColumn)
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, r.parseError(name, i, fmt.Errorf("error parsing %s: %w", reflect.Int, err))
+	}
+	return n, nil
+}
+
+// Float parses the cell in the named column as a float64.
+func (r Row) Float(name string) (float64, error) {
+	s, i := r.lookup(name)
+	if i == -1 {
+		return 0, r.parseError(name, i, ErrMissingColumn)
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, r.parseError(name, i, fmt.Errorf("error parsing %s: %w", reflect.Float64, err))
+	}
+	return f, nil
+}
+
+// lookup returns the cell in the named column and its position, or -1 if
+// there is no such column.
+func (r Row) lookup(name string) (string, int) {
+	i, ok := r.header.index(name)
+	if !ok || i >= len(r.cells) {
+		return "", -1
+	}
+	return r.cells[i], i
+}
+
+func (r Row) parseError(name string, i int, err error) error {
+	pe := &ParseError{Line: r.line, Column: i + 1, Header: name, Err: err}
+	if i != -1 {
+		pe.Header = r.header.names[i]
+		pe.Value = r.cells[i]
+	}
+	return pe
+}
+
+// Decode decodes row into v, as a CSVUnmarshaler[T] reading the same file
+// would, including the header policy and other options of the reader.
+func Decode[T any](row Row, v *T) error {
+	t := reflect.TypeFor[T]()
+	b, err := row.header.binding(t, v)
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal: %w", err)
+	}
+
+	var zero T
+	*v = zero
+
+	var generated RowUnmarshaler
+	if b.generated {
+		generated = any(v).(RowUnmarshaler)
+	}
+	line := func(int) int { return row.line }
+
+	return b.decode(reflect.ValueOf(v).Elem(), generated, row.cells, line)
+}
+
+// binding returns the binding of the header to t, creating it the first time.
+// v is a *t, used to check for generated methods.
+func (h *dynamicHeader) binding(t reflect.Type, v any) (*rowBinding, error) {
+	if b, ok := h.bindings.Load(t); ok {
+		rb := b.(*rowBinding)
+		return rb, rb.err
+	}
+
+	rb := &rowBinding{}
+	rb.err = func() error {
+		c, err := codecFor(t)
+		if err != nil {
+			return err
+		}
+		if rb.binding, err = newBinding(c, h.names, h.opts); err != nil {
+			return err
+		}
+		if !h.opts.disableGenerated {
+			generated, err := generatedUnmarshaler(v, c)
+			if err != nil {
+				return err
+			}
+			rb.generated = generated != nil
+		}
+		return rb.strict(h.opts)
+	}()
+
+	b, _ := h.bindings.LoadOrStore(t, rb)
+	rb = b.(*rowBinding)
+	return rb, rb.err
+}
@@ -0,0 +1,199 @@
+package csvmum
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamicReader(t *testing.T) {
+	t.Parallel()
+
+	input := "stop_id,stop_name,stop_lat,location_type,vendor_x\n" +
+		"S1,Main St,34.05,1,x1\n" +
+		"S2,\"Broadway\nNorth\",north,,x2\n"
+
+	t.Run("accessors", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		dr, err := NewDynamicReader(bytes.NewBufferString(input))
+		assert.NoError(err)
+		assert.Equal([]string{"stop_id", "stop_name", "stop_lat", "location_type", "vendor_x"}, dr.Header())
+
+		rows, err := dr.ReadAll()
+		assert.NoError(err)
+		if !assert.Len(rows, 2) {
+			return
+		}
+
+		r := rows[0]
+		assert.Equal(2, r.Line())
+		assert.True(r.Has("stop_id"))
+		assert.False(r.Has("parent_station"))
+		assert.Equal("S1", r.String("stop_id"))
+		assert.Equal("", r.String("parent_station"))
+		assert.Equal([]string{"S1", "Main St", "34.05", "1", "x1"}, r.Values())
+
+		f, err := r.Float("stop_lat")
+		assert.NoError(err)
+		assert.Equal(34.05, f)
+
+		i, err := r.Int("location_type")
+		assert.NoError(err)
+		assert.Equal(1, i)
+
+		_, err = r.Int("parent_station")
+		assert.ErrorIs(err, ErrMissingColumn)
+		assert.EqualError(err, `cannot unmarshal line 2, column "parent_station", value "": missing column`)
+
+		r = rows[1]
+		assert.Equal(3, r.Line())
+		assert.Equal("Broadway\nNorth", r.String("stop_name"))
+
+		_, err = r.Float("stop_lat")
+		var pe *ParseError
+		if assert.ErrorAs(err, &pe) {
+			assert.Equal(3, pe.Column)
+			assert.Equal("north", pe.Value)
+		}
+		assert.EqualError(err, `cannot unmarshal line 3, column "stop_lat", value "north": error parsing float64: strconv.ParseFloat: parsing "north": invalid syntax`)
+
+		_, err = r.Int("location_type")
+		assert.EqualError(err, `cannot unmarshal line 3, column "location_type", value "": error parsing int: strconv.Atoi: parsing "": invalid syntax`)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type stop struct {
+			ID   string            `csv:"stop_id"`
+			Name string            `csv:"stop_name"`
+			Lat  float64           `csv:"stop_lat"`
+			Type int               `csv:"location_type,default=0"`
+			More map[string]string `csv:",extra"`
+		}
+
+		dr, err := NewDynamicReader(bytes.NewBufferString(input))
+		assert.NoError(err)
+
+		r, err := dr.Read()
+		assert.NoError(err)
+
+		var s stop
+		assert.NoError(Decode(r, &s))
+		assert.Equal(stop{ID: "S1", Name: "Main St", Lat: 34.05, Type: 1, More: map[string]string{"vendor_x": "x1"}}, s)
+
+		r, err = dr.Read()
+		assert.NoError(err)
+
+		err = Decode(r, &s)
+		assert.EqualError(err, `cannot unmarshal line 3, column "stop_lat", field Lat, value "north": error parsing float64: strconv.ParseFloat: parsing "north": invalid syntax`)
+
+		type required struct {
+			Parent string `csv:"parent_station,required"`
+		}
+		assert.EqualError(Decode(r, &required{}), "cannot unmarshal: missing required column: parent_station")
+
+		_, err = dr.Read()
+		assert.Equal(io.EOF, err)
+	})
+
+	t.Run("header policy", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		input := "Stop_ID,stop_name,stop_name\nS1,Main St,Main Street\n"
+
+		dr, err := NewDynamicReader(bytes.NewBufferString(input), WithHeaderPolicy(HeaderWarn))
+		assert.NoError(err)
+		assert.Equal([]HeaderDiagnostic{{Kind: DuplicateColumn, Column: 3, Header: "stop_name", Name: "stop_name"}}, dr.Diagnostics())
+
+		// the policy does not change how columns are looked up
+		r, err := dr.Read()
+		assert.NoError(err)
+		assert.False(r.Has("stop_id"))
+		assert.Equal("S1", r.String("Stop_ID"))
+		assert.Equal("Main St", r.String("stop_name"))
+
+		dr, err = NewDynamicReader(bytes.NewBufferString(input))
+		assert.NoError(err)
+		assert.Empty(dr.Diagnostics())
+		r, err = dr.Read()
+		assert.NoError(err)
+		assert.False(r.Has("stop_id"))
+
+		_, err = NewDynamicReader(bytes.NewBufferString(input), WithHeaderPolicy(HeaderStrict))
+		assert.EqualError(err, `cannot unmarshal: invalid header: duplicate column 3: "stop_name"`)
+	})
+
+	t.Run("normalization", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		input := "\ufeffstop_id\t stop_name\nS1\t Caf\xe9 \n"
+
+		dr, err := NewDynamicReader(bytes.NewBufferString(input), StripBOM(), TSV(), TrimSpace(), WithEncoding(Latin1))
+		assert.NoError(err)
+		assert.Equal([]string{"stop_id", "stop_name"}, dr.Header())
+
+		r, err := dr.Read()
+		assert.NoError(err)
+		assert.Equal("Café", r.String("stop_name"))
+	})
+
+	t.Run("bom before quoted header", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		f, err := os.Open("testdata/bomquoted.csv")
+		if !assert.NoError(err) {
+			return
+		}
+		defer f.Close()
+
+		dr, err := NewDynamicReader(f, StripBOM())
+		assert.NoError(err)
+		assert.Equal([]string{"agency_id", "agency_name"}, dr.Header())
+	})
+
+	t.Run("reused records", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		c := csv.NewReader(bytes.NewBufferString("id\none\ntwo\n"))
+		c.ReuseRecord = true
+
+		dr, err := NewCSVDynamicReader(c)
+		assert.NoError(err)
+
+		rows, err := dr.ReadAll()
+		assert.NoError(err)
+		assert.Equal("one", rows[0].String("id"))
+		assert.Equal("two", rows[1].String("id"))
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		dr, err := NewDynamicReader(bytes.NewBufferString("id,name\none,1\ntwo\nthree,3\n"), ContinueOnError())
+		assert.NoError(err)
+
+		rows, err := dr.ReadAll()
+		assert.EqualError(err, "cannot unmarshal: record on line 3: wrong number of fields")
+		assert.Len(rows, 2)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewDynamicReader(&bytes.Buffer{})
+		assert.Equal(t, io.EOF, err)
+	})
+}
@@ -0,0 +1,42 @@
+package csvmum
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRequired is the cause of a ParseError for an empty cell in a required
+// column.
+var ErrRequired = errors.New("required value is empty")
+
+// ErrMissingColumn is the cause of a ParseError from a Row accessor for a
+// column that is not in the header.
+var ErrMissingColumn = errors.New("missing column")
+
+// ParseError is returned by Unmarshal when a cell cannot be unmarshaled into
+// its field, and by the accessors of Row when a cell cannot be parsed.
+type ParseError struct {
+	// Line is the 1-based line of the cell in the input.
+	Line int
+	// Column is the 1-based position of the column in the header, or 0 if the
+	// column is missing.
+	Column int
+	// Header is the name of the column.
+	Header string
+	// Field is the name of the struct field, if any.
+	Field string
+	// Value is the raw text of the cell.
+	Value string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("cannot unmarshal line %d, column %q, value %q: %v", e.Line, e.Header, e.Value, e.Err)
+	}
+	return fmt.Sprintf("cannot unmarshal line %d, column %q, field %s, value %q: %v", e.Line, e.Header, e.Field, e.Value, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -55,3 +55,18 @@ func (c *customMarshalAndUnmarshal) UnmarshalText(text []byte) error {
 	c.One = string(text[1 : len(text)-1])
 	return nil
 }
+
+type pointerMarshaler struct {
+	One string
+}
+
+func (p *pointerMarshaler) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("<%s>", p.One)), nil
+}
+
+// fixtureInner is unexported so that embedding it tests promoted fields of an
+// unexported type.
+type fixtureInner struct {
+	One string `csv:"one"`
+	Two int
+}
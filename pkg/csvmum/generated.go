@@ -0,0 +1,115 @@
+package csvmum
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// RowUnmarshaler is implemented by pointers to types with methods generated
+// by csvmumgen. The j-th column of the type is record[columns[j]], or missing
+// from the input if columns[j] is -1. Errors for a column are returned as a
+// *FieldError.
+type RowUnmarshaler interface {
+	UnmarshalCSVRow(record []string, columns []int) error
+}
+
+// RowMarshaler is implemented by pointers to types with methods generated by
+// csvmumgen. It fills row with one cell for each column of the type.
+type RowMarshaler interface {
+	MarshalCSVRow(row []string) error
+}
+
+// ColumnLister is implemented by types with methods generated by csvmumgen, so
+// generated code that no longer matches its type can be detected.
+type ColumnLister interface {
+	CSVColumns() []string
+}
+
+// FieldError is returned by generated code when the j-th column of the type
+// cannot be unmarshaled.
+type FieldError struct {
+	Index int
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("column %d: %v", e.Index, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Generated code is not used for types with a field handled by a parser
+// registered with RegisterTypeParser, since csvmumgen cannot see it.
+func generatedUnmarshaler(v any, c *codec) (RowUnmarshaler, error) {
+	g, ok := v.(RowUnmarshaler)
+	if !ok || c.typeParsers {
+		return nil, nil
+	}
+	if ok, err := checkGenerated(v, c); !ok {
+		return nil, err
+	}
+	return g, nil
+}
+
+func generatedMarshaler(v any, c *codec) (RowMarshaler, error) {
+	g, ok := v.(RowMarshaler)
+	if !ok || c.typeParsers {
+		return nil, nil
+	}
+	if ok, err := checkGenerated(v, c); !ok {
+		return nil, err
+	}
+	return g, nil
+}
+
+// checkGenerated reports whether the generated methods of v can be used. Its
+// columns must match those of the codec, unless the methods may have been
+// promoted from an embedded struct, in which case reflection is used instead.
+func checkGenerated(v any, c *codec) (bool, error) {
+	cl, ok := v.(ColumnLister)
+	if !ok {
+		return false, fmt.Errorf("generated code for %T does not list its columns", v)
+	}
+
+	names := make([]string, len(c.fields))
+	for j, fd := range c.fields {
+		names[j] = fd.name
+	}
+
+	if !slices.Equal(names, cl.CSVColumns()) {
+		if embedsGenerated(reflect.TypeOf(v).Elem()) {
+			return false, nil
+		}
+		return false, fmt.Errorf("generated code for %T is out of date", v)
+	}
+
+	return true, nil
+}
+
+var columnListerType = reflect.TypeOf((*ColumnLister)(nil)).Elem()
+
+// embedsGenerated reports whether struct type t embeds, at any depth, a type
+// with generated methods that would be promoted to t.
+func embedsGenerated(t reflect.Type) bool {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.Anonymous {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if reflect.PointerTo(ft).Implements(columnListerType) {
+			return true
+		}
+		if ft.Kind() == reflect.Struct && embedsGenerated(ft) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package csvmum
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// generatedRecord has methods in the form csvmumgen emits, and counts the
+// rows they handle.
+type generatedRecord struct {
+	Name  string `csv:"name,required"`
+	Count int    `csv:"count"`
+}
+
+func (r *generatedRecord) CSVColumns() []string {
+	return []string{"name", "count"}
+}
+
+func (r *generatedRecord) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &FieldError{Index: 0, Err: ErrRequired}
+		}
+		r.Name = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &FieldError{Index: 1, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.Count = int(v)
+	}
+
+	generatedCalls++
+	return nil
+}
+
+func (r *generatedRecord) MarshalCSVRow(row []string) error {
+	row[0] = r.Name
+	row[1] = strconv.FormatInt(int64(r.Count), 10)
+
+	generatedCalls++
+	return nil
+}
+
+var generatedCalls int
+
+type staleRecord struct {
+	Name string `csv:"name"`
+	Code string `csv:"code"`
+}
+
+func (r *staleRecord) CSVColumns() []string {
+	return []string{"name"}
+}
+
+func (r *staleRecord) UnmarshalCSVRow(record []string, columns []int) error {
+	return nil
+}
+
+type unlistedRecord struct {
+	Name string `csv:"name"`
+}
+
+func (r *unlistedRecord) MarshalCSVRow(row []string) error {
+	return nil
+}
+
+func TestGenerated(t *testing.T) {
+	// Not parallel, since generatedCalls is shared.
+
+	t.Run("unmarshal", func(t *testing.T) {
+		assert := assert.New(t)
+		generatedCalls = 0
+
+		um, err := NewUnmarshaler[generatedRecord](bytes.NewBufferString("count,name\n1,one\nx,two\n3,\n"))
+		assert.NoError(err)
+
+		var r generatedRecord
+		assert.NoError(um.Unmarshal(&r))
+		assert.Equal(generatedRecord{Name: "one", Count: 1}, r)
+
+		assert.EqualError(um.Unmarshal(&r), `cannot unmarshal line 3, column "count", field Count, value "x": error parsing int: strconv.ParseInt: parsing "x": invalid syntax`)
+		assert.EqualError(um.Unmarshal(&r), `cannot unmarshal line 4, column "name", field Name, value "": required value is empty`)
+
+		assert.Equal(1, generatedCalls)
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		assert := assert.New(t)
+		generatedCalls = 0
+
+		b := &bytes.Buffer{}
+		m, err := NewMarshaler[generatedRecord](b)
+		assert.NoError(err)
+		assert.NoError(m.Marshal(generatedRecord{Name: "one", Count: 1}))
+		m.Flush()
+
+		assert.Equal("name,count\none,1\n", b.String())
+		assert.Equal(1, generatedCalls)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert := assert.New(t)
+		generatedCalls = 0
+
+		um, err := NewUnmarshaler[generatedRecord](bytes.NewBufferString("name,count\none,1\n"), DisableGenerated())
+		assert.NoError(err)
+
+		var r generatedRecord
+		assert.NoError(um.Unmarshal(&r))
+		assert.Equal(generatedRecord{Name: "one", Count: 1}, r)
+
+		b := &bytes.Buffer{}
+		m, err := NewMarshaler[generatedRecord](b, DisableGenerated())
+		assert.NoError(err)
+		assert.NoError(m.Marshal(r))
+		m.Flush()
+
+		assert.Equal("name,count\none,1\n", b.String())
+		assert.Equal(0, generatedCalls)
+	})
+
+	t.Run("dynamic", func(t *testing.T) {
+		assert := assert.New(t)
+		generatedCalls = 0
+
+		dr, err := NewDynamicReader(bytes.NewBufferString("count,name\n1,one\nx,two\n"))
+		assert.NoError(err)
+
+		var r generatedRecord
+		row, err := dr.Read()
+		assert.NoError(err)
+		assert.NoError(Decode(row, &r))
+		assert.Equal(generatedRecord{Name: "one", Count: 1}, r)
+
+		row, err = dr.Read()
+		assert.NoError(err)
+		assert.EqualError(Decode(row, &r), `cannot unmarshal line 3, column "count", field Count, value "x": error parsing int: strconv.ParseInt: parsing "x": invalid syntax`)
+
+		assert.Equal(1, generatedCalls)
+	})
+
+	t.Run("out of date", func(t *testing.T) {
+		assert := assert.New(t)
+
+		_, err := NewUnmarshaler[staleRecord](bytes.NewBufferString("name,code\none,1\n"))
+		assert.EqualError(err, "cannot unmarshal: generated code for *csvmum.staleRecord is out of date")
+
+		_, err = NewUnmarshaler[staleRecord](bytes.NewBufferString("name,code\none,1\n"), DisableGenerated())
+		assert.NoError(err)
+	})
+
+	t.Run("promoted", func(t *testing.T) {
+		assert := assert.New(t)
+		generatedCalls = 0
+
+		um, err := NewUnmarshaler[embeddingRecord](bytes.NewBufferString("name,count,extra\none,1,more\n"))
+		assert.NoError(err)
+
+		var r embeddingRecord
+		assert.NoError(um.Unmarshal(&r))
+		assert.Equal(embeddingRecord{generatedRecord: generatedRecord{Name: "one", Count: 1}, Extra: "more"}, r)
+		assert.Equal(0, generatedCalls)
+	})
+
+	t.Run("no columns", func(t *testing.T) {
+		assert := assert.New(t)
+
+		_, err := NewMarshaler[unlistedRecord](&bytes.Buffer{})
+		assert.EqualError(err, "cannot marshal: generated code for *csvmum.unlistedRecord does not list its columns")
+	})
+}
+
+type embeddingRecord struct {
+	generatedRecord
+	Extra string `csv:"extra"`
+}
@@ -0,0 +1,135 @@
+package csvmum
+
+import (
+	"fmt"
+	"strings"
+)
+
+type HeaderDiagnosticKind int
+
+const (
+	UnknownColumn HeaderDiagnosticKind = iota + 1
+	MissingColumn
+	DuplicateColumn
+	MismatchedColumn
+)
+
+func (k HeaderDiagnosticKind) String() string {
+	switch k {
+	case UnknownColumn:
+		return "unknown column"
+	case MissingColumn:
+		return "missing column"
+	case DuplicateColumn:
+		return "duplicate column"
+	case MismatchedColumn:
+		return "mismatched column"
+	}
+	return fmt.Sprintf("HeaderDiagnosticKind(%d)", int(k))
+}
+
+type HeaderDiagnostic struct {
+	Kind HeaderDiagnosticKind
+	// Column is the 1-based position of the column in the header, or 0 for
+	// missing columns.
+	Column int
+	// Header is the column name as it appears in the header.
+	Header string
+	// Name is the column name expected by the struct, if any.
+	Name string
+}
+
+func (d HeaderDiagnostic) String() string {
+	switch d.Kind {
+	case UnknownColumn:
+		return fmt.Sprintf("unknown column %d: %q", d.Column, d.Header)
+	case MissingColumn:
+		return fmt.Sprintf("missing column: %q", d.Name)
+	case DuplicateColumn:
+		return fmt.Sprintf("duplicate column %d: %q", d.Column, d.Header)
+	case MismatchedColumn:
+		return fmt.Sprintf("mismatched column %d: %q should be %q", d.Column, d.Header, d.Name)
+	}
+	return fmt.Sprintf("%s %d: %q", d.Kind, d.Column, d.Header)
+}
+
+type HeaderError struct {
+	Diagnostics []HeaderDiagnostic
+}
+
+func (e *HeaderError) Error() string {
+	dd := make([]string, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		dd[i] = d.String()
+	}
+	return "invalid header: " + strings.Join(dd, "; ")
+}
+
+type headerMap struct {
+	fieldList   []int
+	found       []bool
+	diagnostics []HeaderDiagnostic
+}
+
+// mapHeader maps header hh to fields by exact name, whatever the policy, so
+// that the policy only changes what is reported. Columns differing from a
+// field name only by case or surrounding whitespace are reported as
+// mismatched, rather than unknown, but are not mapped.
+func mapHeader(fields []field, hh []string, policy HeaderPolicy) headerMap {
+	hm := headerMap{
+		fieldList: make([]int, len(hh)),
+		found:     make([]bool, len(fields)),
+	}
+
+	exact := make(map[string]int, len(fields))
+	for j, fd := range fields {
+		exact[fd.name] = j
+	}
+
+	loose := make(map[string]int, len(fields))
+	for j, fd := range fields {
+		loose[normalizeHeader(fd.name)] = j
+	}
+	// mismatched holds the fields named by a mismatched column, which are
+	// not reported as missing as well
+	mismatched := make([]bool, len(fields))
+
+	for i, h := range hh {
+		j, ok := exact[h]
+		if !ok {
+			hm.fieldList[i] = -1
+			if j, ok := loose[normalizeHeader(h)]; ok {
+				mismatched[j] = true
+				hm.diagnose(HeaderDiagnostic{Kind: MismatchedColumn, Column: i + 1, Header: h, Name: fields[j].name}, policy)
+			} else {
+				hm.diagnose(HeaderDiagnostic{Kind: UnknownColumn, Column: i + 1, Header: h}, policy)
+			}
+			continue
+		}
+
+		if hm.found[j] {
+			hm.diagnose(HeaderDiagnostic{Kind: DuplicateColumn, Column: i + 1, Header: h, Name: fields[j].name}, policy)
+		}
+
+		hm.fieldList[i] = j
+		hm.found[j] = true
+	}
+
+	for j, fd := range fields {
+		if !hm.found[j] && !mismatched[j] {
+			hm.diagnose(HeaderDiagnostic{Kind: MissingColumn, Name: fd.name}, policy)
+		}
+	}
+
+	return hm
+}
+
+func (hm *headerMap) diagnose(d HeaderDiagnostic, policy HeaderPolicy) {
+	if policy != HeaderLenient {
+		hm.diagnostics = append(hm.diagnostics, d)
+	}
+}
+
+func normalizeHeader(h string) string {
+	return strings.ToLower(strings.TrimSpace(h))
+}
@@ -0,0 +1,114 @@
+package csvmum
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapHeader(t *testing.T) {
+	t.Parallel()
+
+	fields := []field{{name: "one", index: []int{0}}, {name: "two", index: []int{1}}, {name: "three", index: []int{2}}}
+
+	tt := []struct {
+		name        string
+		header      []string
+		policy      HeaderPolicy
+		fieldList   []int
+		found       []bool
+		diagnostics []HeaderDiagnostic
+	}{{
+		name:      "exact",
+		header:    []string{"one", "two", "three"},
+		policy:    HeaderWarn,
+		fieldList: []int{0, 1, 2},
+		found:     []bool{true, true, true},
+	}, {
+		name:      "lenient",
+		header:    []string{"One", "two", "two", "four"},
+		policy:    HeaderLenient,
+		fieldList: []int{-1, 1, 1, -1},
+		found:     []bool{false, true, false},
+	}, {
+		name:      "unknown",
+		header:    []string{"one", "two", "three", "four"},
+		policy:    HeaderWarn,
+		fieldList: []int{0, 1, 2, -1},
+		found:     []bool{true, true, true},
+		diagnostics: []HeaderDiagnostic{
+			{Kind: UnknownColumn, Column: 4, Header: "four"},
+		},
+	}, {
+		name:      "missing",
+		header:    []string{"three", "one"},
+		policy:    HeaderWarn,
+		fieldList: []int{2, 0},
+		found:     []bool{true, false, true},
+		diagnostics: []HeaderDiagnostic{
+			{Kind: MissingColumn, Name: "two"},
+		},
+	}, {
+		name:      "duplicate",
+		header:    []string{"one", "two", "three", "two"},
+		policy:    HeaderWarn,
+		fieldList: []int{0, 1, 2, 1},
+		found:     []bool{true, true, true},
+		diagnostics: []HeaderDiagnostic{
+			{Kind: DuplicateColumn, Column: 4, Header: "two", Name: "two"},
+		},
+	}, {
+		name:      "mismatched",
+		header:    []string{" One", "TWO ", "three"},
+		policy:    HeaderStrict,
+		fieldList: []int{-1, -1, 2},
+		found:     []bool{false, false, true},
+		diagnostics: []HeaderDiagnostic{
+			{Kind: MismatchedColumn, Column: 1, Header: " One", Name: "one"},
+			{Kind: MismatchedColumn, Column: 2, Header: "TWO ", Name: "two"},
+		},
+	}, {
+		name:      "mismatched lenient",
+		header:    []string{" One", "TWO ", "three"},
+		policy:    HeaderLenient,
+		fieldList: []int{-1, -1, 2},
+		found:     []bool{false, false, true},
+	}, {
+		name:      "misspelled",
+		header:    []string{"one", "tow", "three"},
+		policy:    HeaderWarn,
+		fieldList: []int{0, -1, 2},
+		found:     []bool{true, false, true},
+		diagnostics: []HeaderDiagnostic{
+			{Kind: UnknownColumn, Column: 2, Header: "tow"},
+			{Kind: MissingColumn, Name: "two"},
+		},
+	}}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			hm := mapHeader(fields, tc.header, tc.policy)
+			assert.Equal(tc.fieldList, hm.fieldList)
+			assert.Equal(tc.found, hm.found)
+			assert.Equal(tc.diagnostics, hm.diagnostics)
+		})
+	}
+}
+
+func TestHeaderError(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	err := &HeaderError{Diagnostics: []HeaderDiagnostic{
+		{Kind: UnknownColumn, Column: 4, Header: "four"},
+		{Kind: MissingColumn, Name: "two"},
+		{Kind: DuplicateColumn, Column: 3, Header: "one", Name: "one"},
+		{Kind: MismatchedColumn, Column: 1, Header: "One", Name: "one"},
+	}}
+
+	assert.EqualError(err, `invalid header: unknown column 4: "four"; missing column: "two"; duplicate column 3: "one"; mismatched column 1: "One" should be "one"`)
+}
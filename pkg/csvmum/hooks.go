@@ -0,0 +1,60 @@
+package csvmum
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AfterUnmarshaler is implemented by types that validate or complete
+// themselves once a record has been unmarshaled into them. An error is
+// returned from Unmarshal as a *RecordError, so that ContinueOnError skips
+// the record as it would one with a cell that cannot be unmarshaled.
+type AfterUnmarshaler interface {
+	AfterUnmarshalCSV() error
+}
+
+// BeforeMarshaler is implemented by types that validate or prepare themselves
+// before being marshaled. It is called on a copy of the value passed to
+// Marshal, so it may set fields without changing the caller's value.
+type BeforeMarshaler interface {
+	BeforeMarshalCSV() error
+}
+
+// RecordError is returned by Unmarshal when AfterUnmarshalCSV fails.
+type RecordError struct {
+	// Line is the 1-based line the record starts on.
+	Line int
+	Err  error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("cannot unmarshal line %d: %v", e.Line, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// afterUnmarshal calls the AfterUnmarshalCSV method of v, if it has one.
+func afterUnmarshal(v reflect.Value, line int) error {
+	h, ok := v.Addr().Interface().(AfterUnmarshaler)
+	if !ok {
+		return nil
+	}
+	if err := h.AfterUnmarshalCSV(); err != nil {
+		return &RecordError{Line: line, Err: err}
+	}
+	return nil
+}
+
+// beforeMarshal calls the BeforeMarshalCSV method of v, if it has one.
+func beforeMarshal(v any) error {
+	h, ok := v.(BeforeMarshaler)
+	if !ok {
+		return nil
+	}
+	if err := h.BeforeMarshalCSV(); err != nil {
+		return fmt.Errorf("cannot marshal: %w", err)
+	}
+	return nil
+}
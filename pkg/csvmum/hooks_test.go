@@ -0,0 +1,99 @@
+package csvmum
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hooked struct {
+	Name  string `csv:"name"`
+	Upper string `csv:"upper"`
+}
+
+func (h *hooked) AfterUnmarshalCSV() error {
+	if h.Name == "" {
+		return errors.New("name is empty")
+	}
+	h.Upper = strings.ToUpper(h.Name)
+	return nil
+}
+
+func (h *hooked) BeforeMarshalCSV() error {
+	if strings.Contains(h.Name, ",") {
+		return fmt.Errorf("name contains a comma: %s", h.Name)
+	}
+	h.Upper = strings.ToUpper(h.Name)
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("after unmarshal", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		um, err := NewUnmarshaler[hooked](bytes.NewBufferString("name\none\n\nthree\n\"\"\n"), ContinueOnError())
+		assert.NoError(err)
+
+		records, err := um.ReadAll()
+		assert.EqualError(err, "cannot unmarshal line 5: name is empty")
+		assert.Equal([]hooked{{Name: "one", Upper: "ONE"}, {Name: "three", Upper: "THREE"}}, records)
+
+		var re *RecordError
+		assert.ErrorAs(err, &re)
+	})
+
+	t.Run("after unmarshal stops", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		um, err := NewUnmarshaler[hooked](bytes.NewBufferString("name,upper\n\"\",x\ntwo,\n"))
+		assert.NoError(err)
+
+		r := hooked{Name: "before"}
+		assert.EqualError(um.Unmarshal(&r), "cannot unmarshal line 2: name is empty")
+		assert.Equal(hooked{Name: "before"}, r)
+
+		records, err := um.ReadAll()
+		assert.NoError(err)
+		assert.Equal([]hooked{{Name: "two", Upper: "TWO"}}, records)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		dr, err := NewDynamicReader(bytes.NewBufferString("name\none\n\"\"\n"))
+		assert.NoError(err)
+		rows, err := dr.ReadAll()
+		assert.NoError(err)
+
+		var h hooked
+		assert.NoError(Decode(rows[0], &h))
+		assert.Equal(hooked{Name: "one", Upper: "ONE"}, h)
+		assert.EqualError(Decode(rows[1], &h), "cannot unmarshal line 3: name is empty")
+	})
+
+	t.Run("before marshal", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		records := []hooked{{Name: "one"}, {Name: "two, three"}, {Name: "four"}}
+
+		b := &bytes.Buffer{}
+		m, err := NewMarshaler[hooked](b, ContinueOnError())
+		assert.NoError(err)
+
+		err = m.WriteAll(slices.Values(records))
+		assert.EqualError(err, "cannot marshal: name contains a comma: two, three")
+		assert.Equal("name,upper\none,ONE\nfour,FOUR\n", b.String())
+		assert.Equal(hooked{Name: "one"}, records[0])
+	})
+}
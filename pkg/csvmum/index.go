@@ -0,0 +1,350 @@
+package csvmum
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// indexMagic and indexVersion start the first record of a saved index.
+const (
+	indexMagic   = "csvmum index"
+	indexVersion = "1"
+)
+
+// Index holds the byte offsets of the records of a CSV file, keyed by the
+// value of one of its columns, so that the records with a key can be read
+// without reading the rest of the file.
+type Index struct {
+	// Column is the name of the key column.
+	Column string
+
+	// header is the header as it appears in the file, before normalization
+	header []string
+	keys   []string
+	spans  map[string][]span
+
+	// size and modTime describe the file the index was built from, if any
+	size    int64
+	modTime int64
+}
+
+// span is a run of consecutive records with the same key.
+type span struct {
+	offset int64
+	length int64
+	// line is the line of the first record
+	line int
+	rows int
+}
+
+// BuildIndex reads r and indexes its records by the named column, which is
+// matched as the header policy of opts allows. Keys are normalized like other
+// cells, and records that the csv package cannot read are errors, unless
+// ContinueOnError is given, in which case they are left out.
+func BuildIndex(r io.Reader, column string, opts ...Option) (*Index, error) {
+	o := buildOptions(opts)
+
+	// offsets are in the file, so they count a byte order mark
+	r, skipped := o.stripBOMReader(r)
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	if err := o.configureReader(cr); err != nil {
+		return nil, fmt.Errorf("cannot index: %w", err)
+	}
+
+	hh, err := cr.Read()
+	if err == io.EOF {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot index: %w", err)
+	}
+
+	ix := &Index{Column: column, header: slices.Clone(hh), spans: map[string][]span{}}
+
+	normalized := slices.Clone(hh)
+	o.normalizeHeaderRecord(normalized)
+	col := newDynamicHeader(normalized, o)
+	i, ok := col.index(column)
+	if !ok {
+		return nil, fmt.Errorf("cannot index: missing column: %s", column)
+	}
+
+	for {
+		offset := skipped + cr.InputOffset()
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var pe *csv.ParseError
+			if o.continueOnError && errors.As(err, &pe) {
+				continue
+			}
+			return nil, fmt.Errorf("cannot index: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		o.normalizeRecord(rec)
+
+		ix.add(rec[i], offset, skipped+cr.InputOffset()-offset, line)
+	}
+
+	return ix, nil
+}
+
+func (ix *Index) add(key string, offset, length int64, line int) {
+	spans, ok := ix.spans[key]
+	if !ok {
+		ix.keys = append(ix.keys, key)
+	}
+
+	if n := len(spans); n > 0 && spans[n-1].offset+spans[n-1].length == offset {
+		spans[n-1].length += length
+		spans[n-1].rows++
+		return
+	}
+	ix.spans[key] = append(spans, span{offset: offset, length: length, line: line, rows: 1})
+}
+
+// Keys returns the keys of the index, in the order they first appear in the
+// file.
+func (ix *Index) Keys() []string {
+	return slices.Clone(ix.keys)
+}
+
+// Count returns the number of records with key.
+func (ix *Index) Count(key string) int {
+	n := 0
+	for _, sp := range ix.spans[key] {
+		n += sp.rows
+	}
+	return n
+}
+
+// WriteTo saves the index to w, for ReadIndex.
+func (ix *Index) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	c := csv.NewWriter(cw)
+
+	c.Write([]string{indexMagic, indexVersion, ix.Column, strconv.FormatInt(ix.size, 10), strconv.FormatInt(ix.modTime, 10)})
+	c.Write(ix.header)
+	for _, key := range ix.keys {
+		rec := []string{key}
+		for _, sp := range ix.spans[key] {
+			rec = append(rec,
+				strconv.FormatInt(sp.offset, 10),
+				strconv.FormatInt(sp.length, 10),
+				strconv.Itoa(sp.line),
+				strconv.Itoa(sp.rows))
+		}
+		c.Write(rec)
+	}
+	c.Flush()
+
+	if err := c.Error(); err != nil {
+		return cw.n, fmt.Errorf("cannot write index: %w", err)
+	}
+	return cw.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	w.n += int64(n)
+	return n, err
+}
+
+// ReadIndex reads an index saved by Index.WriteTo.
+func ReadIndex(r io.Reader) (*Index, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	rec, err := cr.Read()
+	if err != nil || len(rec) != 5 || rec[0] != indexMagic {
+		return nil, fmt.Errorf("cannot read index: not an index")
+	}
+	if rec[1] != indexVersion {
+		return nil, fmt.Errorf("cannot read index: unsupported version %s", rec[1])
+	}
+
+	ix := &Index{Column: rec[2], spans: map[string][]span{}}
+	if ix.size, err = strconv.ParseInt(rec[3], 10, 64); err != nil {
+		return nil, fmt.Errorf("cannot read index: invalid size: %w", err)
+	}
+	if ix.modTime, err = strconv.ParseInt(rec[4], 10, 64); err != nil {
+		return nil, fmt.Errorf("cannot read index: invalid modification time: %w", err)
+	}
+
+	if ix.header, err = cr.Read(); err != nil {
+		return nil, fmt.Errorf("cannot read index: %w", err)
+	}
+
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read index: %w", err)
+		}
+		if len(rec)%4 != 1 {
+			return nil, fmt.Errorf("cannot read index: invalid record for key %q", rec[0])
+		}
+
+		key := rec[0]
+		ix.keys = append(ix.keys, key)
+		for k := 1; k < len(rec); k += 4 {
+			var sp span
+			var errs [4]error
+			sp.offset, errs[0] = strconv.ParseInt(rec[k], 10, 64)
+			sp.length, errs[1] = strconv.ParseInt(rec[k+1], 10, 64)
+			sp.line, errs[2] = strconv.Atoi(rec[k+2])
+			sp.rows, errs[3] = strconv.Atoi(rec[k+3])
+			if err := errors.Join(errs[:]...); err != nil {
+				return nil, fmt.Errorf("cannot read index: invalid record for key %q: %w", key, err)
+			}
+			ix.spans[key] = append(ix.spans[key], sp)
+		}
+	}
+
+	return ix, nil
+}
+
+// IndexPath returns the path of the saved index of the file at path.
+func IndexPath(path string) string {
+	return path + ".idx"
+}
+
+// IndexFile returns the index of the file at path by column. It reads the
+// index saved at IndexPath(path) if it was built by column from the file as
+// it is now, judged by its size and modification time, and otherwise builds
+// the index and saves it there.
+func IndexFile(path, column string, opts ...Option) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot index: %w", err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("cannot index: %w", err)
+	}
+	size, modTime := fi.Size(), fi.ModTime().UnixNano()
+
+	if saved, err := os.Open(IndexPath(path)); err == nil {
+		ix, err := ReadIndex(saved)
+		saved.Close()
+		if err == nil && ix.Column == column && ix.size == size && ix.modTime == modTime {
+			return ix, nil
+		}
+	}
+
+	ix, err := BuildIndex(f, column, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ix.size, ix.modTime = size, modTime
+
+	out, err := os.Create(IndexPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("cannot index: %w", err)
+	}
+	if _, err := ix.WriteTo(out); err != nil {
+		out.Close()
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, fmt.Errorf("cannot index: %w", err)
+	}
+
+	return ix, nil
+}
+
+// IndexedReader reads the records with a given key from an indexed file.
+// Records are unmarshaled by a CSVUnmarshaler[T], with the options given,
+// which should be those the index was built with.
+type IndexedReader[T any] struct {
+	reader io.ReaderAt
+	index  *Index
+	opts   []Option
+
+	// header is the header record of the file, encoded
+	header string
+}
+
+func NewIndexedReader[T any](r io.ReaderAt, ix *Index, opts ...Option) (*IndexedReader[T], error) {
+	o := buildOptions(opts)
+	ir := &IndexedReader[T]{reader: r, index: ix, opts: opts}
+
+	b := &bytes.Buffer{}
+	w := csv.NewWriter(b)
+	if o.comma != 0 {
+		w.Comma = o.comma
+	}
+	w.Write(ix.header)
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return ir, fmt.Errorf("cannot unmarshal: %w", err)
+	}
+	ir.header = b.String()
+
+	return ir, nil
+}
+
+// All returns an iterator over the records with key, in file order, which
+// stops as CSVUnmarshaler.All does.
+func (ir *IndexedReader[T]) All(key string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, sp := range ir.index.spans[key] {
+			section := io.NewSectionReader(ir.reader, sp.offset, sp.length)
+			um, err := NewUnmarshaler[T](io.MultiReader(strings.NewReader(ir.header), section), ir.opts...)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			// the header is line 1 of the section, rather than its own line
+			um.lineOffset = sp.line - 2
+
+			for r, err := range um.All() {
+				if !yield(r, err) {
+					return
+				}
+				if err != nil && !(um.opts.continueOnError && isRecordError(err)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Get reads the records with key. With ContinueOnError, the records that
+// could be read are returned along with the joined errors of those that could
+// not.
+func (ir *IndexedReader[T]) Get(key string) ([]T, error) {
+	records := []T{}
+	var errs []error
+
+	for r, err := range ir.All(key) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		records = append(records, r)
+	}
+
+	return records, errors.Join(errs...)
+}
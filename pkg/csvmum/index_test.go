@@ -0,0 +1,217 @@
+package csvmum
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type indexedStopTime struct {
+	TripID   string `csv:"trip_id"`
+	StopID   string `csv:"stop_id"`
+	Sequence int    `csv:"stop_sequence"`
+}
+
+const indexedStopTimes = "trip_id,stop_id,stop_sequence\n" +
+	"T1,S1,1\n" +
+	"T1,S2,2\n" +
+	"T2,S1,1\n" +
+	"T2,\"S\n2\",2\n" +
+	"T1,S3,x\n" +
+	"T2,S3,3\n"
+
+func TestIndex(t *testing.T) {
+	t.Parallel()
+
+	t.Run("build", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ix, err := BuildIndex(strings.NewReader(indexedStopTimes), "trip_id")
+		assert.NoError(err)
+		assert.Equal([]string{"T1", "T2"}, ix.Keys())
+		assert.Equal(3, ix.Count("T1"))
+		assert.Equal(3, ix.Count("T2"))
+		assert.Equal(0, ix.Count("T3"))
+		assert.Equal([]span{{offset: 30, length: 16, line: 2, rows: 2}, {offset: 65, length: 8, line: 7, rows: 1}}, ix.spans["T1"])
+		assert.Equal([]span{{offset: 46, length: 19, line: 4, rows: 2}, {offset: 73, length: 8, line: 8, rows: 1}}, ix.spans["T2"])
+	})
+
+	t.Run("read", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ix, err := BuildIndex(strings.NewReader(indexedStopTimes), "trip_id")
+		assert.NoError(err)
+
+		ir, err := NewIndexedReader[indexedStopTime](strings.NewReader(indexedStopTimes), ix, ContinueOnError())
+		assert.NoError(err)
+
+		records, err := ir.Get("T2")
+		assert.NoError(err)
+		assert.Equal([]indexedStopTime{
+			{TripID: "T2", StopID: "S1", Sequence: 1},
+			{TripID: "T2", StopID: "S\n2", Sequence: 2},
+			{TripID: "T2", StopID: "S3", Sequence: 3},
+		}, records)
+
+		records, err = ir.Get("T1")
+		assert.EqualError(err, `cannot unmarshal line 7, column "stop_sequence", field Sequence, value "x": error parsing int: strconv.ParseInt: parsing "x": invalid syntax`)
+		assert.Equal([]indexedStopTime{
+			{TripID: "T1", StopID: "S1", Sequence: 1},
+			{TripID: "T1", StopID: "S2", Sequence: 2},
+		}, records)
+
+		records, err = ir.Get("T3")
+		assert.NoError(err)
+		assert.Empty(records)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		input := "\ufefftrip_id;stop_id;stop_sequence\n T1 ;S1;1\nT2;S2;2\n"
+		opts := []Option{StripBOM(), TrimSpace(), WithDelimiter(';'), WithHeaderPolicy(HeaderWarn)}
+
+		ix, err := BuildIndex(strings.NewReader(input), "trip_id", opts...)
+		assert.NoError(err)
+		assert.Equal([]string{"T1", "T2"}, ix.Keys())
+
+		ir, err := NewIndexedReader[indexedStopTime](strings.NewReader(input), ix, opts...)
+		assert.NoError(err)
+
+		records, err := ir.Get("T1")
+		assert.NoError(err)
+		assert.Equal([]indexedStopTime{{TripID: "T1", StopID: "S1", Sequence: 1}}, records)
+	})
+
+	t.Run("bom before quoted header", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type agency struct {
+			ID   string `csv:"agency_id"`
+			Name string `csv:"agency_name"`
+		}
+
+		data, err := os.ReadFile("testdata/bomquoted.csv")
+		if !assert.NoError(err) {
+			return
+		}
+
+		ix, err := BuildIndex(bytes.NewReader(data), "agency_id", StripBOM())
+		assert.NoError(err)
+		assert.Equal([]string{"A1", "A2"}, ix.Keys())
+
+		// offsets count the byte order mark, so records are read from the
+		// file as it is
+		ir, err := NewIndexedReader[agency](bytes.NewReader(data), ix, StripBOM())
+		assert.NoError(err)
+
+		records, err := ir.Get("A2")
+		assert.NoError(err)
+		assert.Equal([]agency{{ID: "A2", Name: "Bus"}}, records)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		_, err := BuildIndex(strings.NewReader(indexedStopTimes), "route_id")
+		assert.EqualError(err, "cannot index: missing column: route_id")
+
+		input := "trip_id,stop_id\nT1,S1\nT2\nT3,S3\n"
+		_, err = BuildIndex(strings.NewReader(input), "trip_id")
+		assert.EqualError(err, "cannot index: record on line 3: wrong number of fields")
+
+		ix, err := BuildIndex(strings.NewReader(input), "trip_id", ContinueOnError())
+		assert.NoError(err)
+		assert.Equal([]string{"T1", "T3"}, ix.Keys())
+	})
+
+	t.Run("save", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ix, err := BuildIndex(strings.NewReader(indexedStopTimes), "trip_id")
+		assert.NoError(err)
+
+		b := &bytes.Buffer{}
+		n, err := ix.WriteTo(b)
+		assert.NoError(err)
+		assert.Equal(int64(b.Len()), n)
+		assert.Equal("csvmum index,1,trip_id,0,0\n"+
+			"trip_id,stop_id,stop_sequence\n"+
+			"T1,30,16,2,2,65,8,7,1\n"+
+			"T2,46,19,4,2,73,8,8,1\n", b.String())
+
+		read, err := ReadIndex(b)
+		assert.NoError(err)
+		assert.Equal(ix, read)
+
+		_, err = ReadIndex(strings.NewReader("trip_id,stop_id\n"))
+		assert.EqualError(err, "cannot read index: not an index")
+		_, err = ReadIndex(strings.NewReader("csvmum index,2,trip_id,0,0\n"))
+		assert.EqualError(err, "cannot read index: unsupported version 2")
+		_, err = ReadIndex(strings.NewReader("csvmum index,1,trip_id,0,0\ntrip_id\nT1,30,16\n"))
+		assert.EqualError(err, `cannot read index: invalid record for key "T1"`)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		path := filepath.Join(t.TempDir(), "stop_times.txt")
+		assert.NoError(os.WriteFile(path, []byte(indexedStopTimes), 0o644))
+
+		ix, err := IndexFile(path, "trip_id")
+		assert.NoError(err)
+		assert.FileExists(IndexPath(path))
+		assert.Equal(3, ix.Count("T1"))
+
+		// a saved index is used as long as the file is unchanged
+		saved := strings.Replace(indexedStopTimes, "T1", "T9", 1)
+		ix.keys[0] = "T9"
+		ix.spans["T9"] = ix.spans["T1"]
+		delete(ix.spans, "T1")
+		f, err := os.Create(IndexPath(path))
+		assert.NoError(err)
+		_, err = ix.WriteTo(f)
+		assert.NoError(err)
+		assert.NoError(f.Close())
+
+		ix, err = IndexFile(path, "trip_id")
+		assert.NoError(err)
+		assert.Equal([]string{"T9", "T2"}, ix.Keys())
+
+		// and rebuilt when it changes
+		assert.NoError(os.WriteFile(path, []byte(saved), 0o644))
+		later := time.Now().Add(time.Minute)
+		assert.NoError(os.Chtimes(path, later, later))
+
+		ix, err = IndexFile(path, "trip_id")
+		assert.NoError(err)
+		assert.Equal([]string{"T9", "T1", "T2"}, ix.Keys())
+
+		// or for another column
+		ix, err = IndexFile(path, "stop_id")
+		assert.NoError(err)
+		assert.Equal([]string{"S1", "S2", "S\n2", "S3"}, ix.Keys())
+
+		f, err = os.Open(path)
+		assert.NoError(err)
+		defer f.Close()
+
+		ir, err := NewIndexedReader[indexedStopTime](f, ix)
+		assert.NoError(err)
+		records, err := ir.Get("S3")
+		assert.EqualError(err, `cannot unmarshal line 7, column "stop_sequence", field Sequence, value "x": error parsing int: strconv.ParseInt: parsing "x": invalid syntax`)
+		assert.Empty(records)
+	})
+}
@@ -0,0 +1,130 @@
+package csvmum
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// defaultSeparator separates the elements of slice fields without a sep=
+// option.
+const defaultSeparator = "|"
+
+// isList reports whether t is a slice whose elements are each encoded within
+// a single cell, rather than a type with its own text encoding. Byte slices
+// are the text of the cell, not lists.
+func isList(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && !isBytes(t) &&
+		!reflect.PointerTo(t).Implements(textUnmarshalerType) &&
+		!reflect.PointerTo(t).Implements(textMarshalerType) &&
+		!hasTypeParser(t)
+}
+
+// isBytes reports whether t is a byte slice.
+func isBytes(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+func (opts tagOptions) separator() string {
+	if opts.sep == "" {
+		return defaultSeparator
+	}
+	return opts.sep
+}
+
+func (opts tagOptions) checkSeparator(t reflect.Type) error {
+	if !isList(t) {
+		if opts.sep != "" {
+			return fmt.Errorf("sep= is only for slices")
+		}
+		return nil
+	}
+
+	if isList(t.Elem()) {
+		return fmt.Errorf("nested slices are not supported")
+	}
+	if strings.Contains(opts.sep, `\`) {
+		return fmt.Errorf("separator cannot contain a backslash")
+	}
+	return nil
+}
+
+// listDecoder decodes a cell of elements separated by sep. An empty cell is a
+// nil slice.
+func listDecoder(t reflect.Type, dec decodeFunc, sep string) decodeFunc {
+	return func(v reflect.Value, s string) error {
+		if s == "" {
+			v.SetZero()
+			return nil
+		}
+
+		parts := splitList(s, sep)
+		l := reflect.MakeSlice(t, len(parts), len(parts))
+		for i, p := range parts {
+			if err := dec(l.Index(i), p); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		v.Set(l)
+		return nil
+	}
+}
+
+func listEncoder(enc encodeFunc, sep string) encodeFunc {
+	return func(v reflect.Value) (string, error) {
+		parts := make([]string, v.Len())
+		for i := range parts {
+			s, err := enc(v.Index(i))
+			if err != nil {
+				return "", fmt.Errorf("element %d: %w", i, err)
+			}
+			parts[i] = s
+		}
+		return joinList(parts, sep), nil
+	}
+}
+
+// splitList splits s into the elements of a list cell. Elements are separated
+// by sep, and a backslash makes the byte after it part of the element, so
+// that elements can contain the separator or a backslash.
+func splitList(s, sep string) []string {
+	if !strings.Contains(s, `\`) {
+		return strings.Split(s, sep)
+	}
+
+	parts := []string{}
+	b := strings.Builder{}
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			b.WriteByte(s[i+1])
+			i += 2
+		case strings.HasPrefix(s[i:], sep):
+			parts = append(parts, b.String())
+			b.Reset()
+			i += len(sep)
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return append(parts, b.String())
+}
+
+// joinList joins elements into a list cell, the inverse of splitList. Every
+// backslash, and every byte matching the first byte of sep, is escaped.
+func joinList(elems []string, sep string) string {
+	b := strings.Builder{}
+	for i, e := range elems {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		for j := range len(e) {
+			if c := e[j]; c == '\\' || c == sep[0] {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(e[j])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,192 @@
+package csvmum
+
+import (
+	"bytes"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type listed struct {
+	Notes  []string     `csv:"notes"`
+	Counts []int        `csv:"counts,sep=;"`
+	Codes  []int        `csv:"codes,sep=::,parse=test.hex"`
+	Addrs  []netip.Addr `csv:"addrs,sep= "`
+	Flags  []yesNo      `csv:"flags"`
+	Times  []*float64   `csv:"times,omitempty"`
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unmarshal", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("notes,counts,codes,addrs,flags,times\n" +
+			`one|two\|three|four\\,1;2;3,a::ff,10.0.0.1 ::1,Y|N,1.5` + "\n" +
+			",,,,,\n")
+
+		um, err := NewUnmarshaler[listed](b)
+		assert.NoError(err)
+
+		records, err := um.ReadAll()
+		assert.NoError(err)
+
+		f := 1.5
+		assert.Equal([]listed{{
+			Notes:  []string{"one", "two|three", `four\`},
+			Counts: []int{1, 2, 3},
+			Codes:  []int{10, 255},
+			Addrs:  []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1")},
+			Flags:  []yesNo{true, false},
+			Times:  []*float64{&f},
+		}, {}}, records)
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := &bytes.Buffer{}
+		m, err := NewMarshaler[listed](b)
+		assert.NoError(err)
+		assert.NoError(m.Marshal(listed{
+			Notes:  []string{"one", "two|three", `four\`, ""},
+			Counts: []int{1, 2, 3},
+			Codes:  []int{10, 255},
+			Addrs:  []netip.Addr{netip.MustParseAddr("10.0.0.1")},
+			Flags:  []yesNo{true},
+		}))
+		assert.NoError(m.Marshal(listed{}))
+		m.Flush()
+
+		assert.Equal("notes,counts,codes,addrs,flags,times\n"+
+			`one|two\|three|four\\|,1;2;3,a::ff,10.0.0.1,Y,`+"\n"+
+			",,,,,\n", b.String())
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type blob struct {
+			ID   string `csv:"id"`
+			Data []byte `csv:"data"`
+		}
+
+		b := &bytes.Buffer{}
+		m, err := NewMarshaler[blob](b)
+		assert.NoError(err)
+		assert.NoError(m.Marshal(blob{ID: "1", Data: []byte("a|b")}))
+		assert.NoError(m.Marshal(blob{ID: "2"}))
+		m.Flush()
+		assert.Equal("id,data\n1,a|b\n2,\n", b.String())
+
+		um, err := NewUnmarshaler[blob](b)
+		assert.NoError(err)
+		records, err := um.ReadAll()
+		assert.NoError(err)
+		assert.Equal([]blob{{ID: "1", Data: []byte("a|b")}, {ID: "2"}}, records)
+	})
+
+	t.Run("empty elements", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type notes struct {
+			ID    string   `csv:"id"`
+			Notes []string `csv:"notes"`
+		}
+
+		b := &bytes.Buffer{}
+		m, err := NewMarshaler[notes](b)
+		assert.NoError(err)
+		assert.NoError(m.Marshal(notes{ID: "1", Notes: []string{""}}))
+		assert.NoError(m.Marshal(notes{ID: "2", Notes: []string{"", ""}}))
+		m.Flush()
+
+		um, err := NewUnmarshaler[notes](b)
+		assert.NoError(err)
+		records, err := um.ReadAll()
+		assert.NoError(err)
+
+		// a single empty element is an empty cell, which is a nil slice
+		assert.Equal([]notes{{ID: "1"}, {ID: "2", Notes: []string{"", ""}}}, records)
+	})
+
+	t.Run("element error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		um, err := NewUnmarshaler[listed](bytes.NewBufferString("counts\n1;x\n"))
+		assert.NoError(err)
+
+		_, err = um.ReadAll()
+		assert.EqualError(err, `cannot unmarshal line 2, column "counts", field Counts, value "1;x": element 1: error parsing int: strconv.ParseInt: parsing "x": invalid syntax`)
+	})
+
+	for _, tc := range []struct {
+		name string
+		new  func() error
+		err  string
+	}{{
+		name: "sep on scalar",
+		new: func() error {
+			_, err := NewUnmarshaler[struct {
+				Name string `csv:"name,sep=;"`
+			}](bytes.NewBufferString("name\n"))
+			return err
+		},
+		err: "cannot unmarshal: invalid separator for name: sep= is only for slices",
+	}, {
+		name: "backslash separator",
+		new: func() error {
+			_, err := NewMarshaler[struct {
+				Names []string `csv:"names,sep=\\"`
+			}](&bytes.Buffer{})
+			return err
+		},
+		err: `cannot marshal: invalid separator for names: separator cannot contain a backslash`,
+	}, {
+		name: "nested",
+		new: func() error {
+			_, err := NewMarshaler[struct {
+				Names [][]string `csv:"names"`
+			}](&bytes.Buffer{})
+			return err
+		},
+		err: "cannot marshal: invalid separator for names: nested slices are not supported",
+	}} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.EqualError(t, tc.new(), tc.err)
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		cell  string
+		sep   string
+		elems []string
+	}{
+		{cell: "a", sep: "|", elems: []string{"a"}},
+		{cell: "a|b||c", sep: "|", elems: []string{"a", "b", "", "c"}},
+		{cell: `a\|b|c`, sep: "|", elems: []string{"a|b", "c"}},
+		{cell: `a\\|b`, sep: "|", elems: []string{`a\`, "b"}},
+		{cell: `a\::b::c`, sep: "::", elems: []string{"a::b", "c"}},
+		{cell: `a, b`, sep: ", ", elems: []string{"a", "b"}},
+	} {
+		assert.Equal(t, tc.elems, splitList(tc.cell, tc.sep), tc.cell)
+		assert.Equal(t, tc.elems, splitList(joinList(tc.elems, tc.sep), tc.sep), tc.cell)
+	}
+
+	assert.Equal(t, []string{"a|b"}, splitList(`a\|b`, ";"))
+	assert.Equal(t, []string{`a\`}, splitList(`a\`, "|"))
+}
@@ -1,69 +1,220 @@
 package csvmum
 
 import (
-	"encoding"
+	"cmp"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"reflect"
-	"strconv"
+	"slices"
 )
 
 type CSVMarshaler[T any] struct {
-	writer    *csv.Writer
-	fieldList []int
+	writer rowWriter
+	codec  *codec
+	opts   options
+
+	// record holds an addressable copy of the value being marshaled
+	record    *T
+	value     reflect.Value
+	row       []string
+	generated RowMarshaler
+
+	// extra maps the extra columns to their position in row
+	extra map[string]int
+
+	// columns holds the position in row of each column written, in order, or
+	// is nil if row is written as it is; out holds the row as written
+	columns []int
+	out     []string
 }
 
-func NewMarshaler[T any](w io.Writer) (*CSVMarshaler[T], error) {
+func NewMarshaler[T any](w io.Writer, opts ...Option) (*CSVMarshaler[T], error) {
+	o := buildOptions(opts)
+	if o.quoteAll {
+		comma := o.comma
+		if comma == 0 {
+			comma = ','
+		}
+		return newMarshaler[T](newQuotingWriter(w, comma, o.useCRLF), o)
+	}
+
 	c := csv.NewWriter(w)
 
-	return NewCSVMarshaler[T](c)
+	return NewCSVMarshaler[T](c, opts...)
 }
 
-func NewCSVMarshaler[T any](w *csv.Writer) (*CSVMarshaler[T], error) {
-	m := &CSVMarshaler[T]{writer: w}
+func NewCSVMarshaler[T any](w *csv.Writer, opts ...Option) (*CSVMarshaler[T], error) {
+	o := buildOptions(opts)
+	if o.quoteAll {
+		return &CSVMarshaler[T]{writer: w, opts: o}, fmt.Errorf("cannot marshal: QuoteAll requires NewMarshaler")
+	}
+
+	if o.comma != 0 {
+		w.Comma = o.comma
+	}
+	if o.useCRLF {
+		w.UseCRLF = true
+	}
 
-	var t T
-	hm, err := buildFieldMap(reflect.TypeOf(t))
+	return newMarshaler[T](w, o)
+}
+
+func newMarshaler[T any](w rowWriter, o options) (*CSVMarshaler[T], error) {
+	m := &CSVMarshaler[T]{writer: w, opts: o}
+
+	if o.comma != 0 && !validDelim(o.comma) {
+		return m, fmt.Errorf("cannot marshal: invalid delimiter %q", o.comma)
+	}
+
+	m.record = new(T)
+	m.value = reflect.ValueOf(m.record).Elem()
+
+	c, err := codecFor(m.value.Type())
 	if err != nil {
 		return m, fmt.Errorf("cannot marshal: %w", err)
 	}
+	if c.encodeErr != nil {
+		return m, fmt.Errorf("cannot marshal: %w", c.encodeErr)
+	}
+	m.codec = c
+	fields := c.fields
 
-	hh, fl := getOrderedHeaders(hm)
-	if err = m.writer.Write(hh); err != nil {
+	if !m.opts.disableGenerated {
+		if m.generated, err = generatedMarshaler(m.record, c); err != nil {
+			return m, fmt.Errorf("cannot marshal: %w", err)
+		}
+	}
+
+	hh := make([]string, len(fields), len(fields)+len(m.opts.extraColumns))
+	for i, fd := range fields {
+		hh[i] = fd.name
+	}
+
+	if m.columns, err = columnOrder(fields, m.opts.columns); err != nil {
 		return m, fmt.Errorf("cannot marshal: %w", err)
 	}
 
-	m.fieldList = fl
+	if len(m.opts.extraColumns) > 0 {
+		if c.extra == nil {
+			return m, fmt.Errorf("cannot marshal: extra columns given for %s, which has no extra field", m.value.Type())
+		}
+
+		m.extra = make(map[string]int, len(m.opts.extraColumns))
+		for _, name := range m.opts.extraColumns {
+			if slices.Contains(hh, name) {
+				return m, fmt.Errorf("cannot marshal: duplicate column: %s", name)
+			}
+			m.extra[name] = len(hh)
+			hh = append(hh, name)
+		}
+	}
+
+	m.row = make([]string, len(hh))
+
+	if m.columns != nil {
+		for i := len(fields); i < len(hh); i++ {
+			m.columns = append(m.columns, i)
+		}
+
+		m.out = make([]string, len(m.columns))
+		for i, j := range m.columns {
+			m.out[i] = hh[j]
+		}
+		hh = m.out
+	}
+
+	if !m.opts.noHeader {
+		if err = m.writer.Write(hh); err != nil {
+			return m, fmt.Errorf("cannot marshal: %w", err)
+		}
+	}
 
 	return m, nil
 }
 
+// columnOrder returns the positions in the field list of the columns to write,
+// in order: those named, or those with an order= option by order and then the
+// rest. It returns nil if every column is written in struct order.
+func columnOrder(fields []field, names []string) ([]int, error) {
+	if names != nil {
+		positions := make(map[string]int, len(fields))
+		for i, fd := range fields {
+			positions[fd.name] = i
+		}
+
+		columns := make([]int, len(names))
+		for i, name := range names {
+			j, ok := positions[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown column: %s", name)
+			}
+			if slices.Contains(columns[:i], j) {
+				return nil, fmt.Errorf("duplicate column: %s", name)
+			}
+			columns[i] = j
+		}
+		return columns, nil
+	}
+
+	if !slices.ContainsFunc(fields, func(fd field) bool { return fd.hasOrder }) {
+		return nil, nil
+	}
+
+	columns := make([]int, len(fields))
+	for i := range columns {
+		columns[i] = i
+	}
+	slices.SortStableFunc(columns, func(i, j int) int {
+		a, b := fields[i], fields[j]
+		switch {
+		case a.hasOrder && b.hasOrder:
+			return cmp.Compare(a.position, b.position)
+		case a.hasOrder:
+			return -1
+		case b.hasOrder:
+			return 1
+		}
+		return 0
+	})
+	return columns, nil
+}
+
 func (m *CSVMarshaler[T]) Marshal(record T) error {
-	v := reflect.ValueOf(record)
-	row := make([]string, len(m.fieldList))
+	*m.record = record
 
-	for ci, fi := range m.fieldList {
-		f := v.Field(fi)
-		if cm, ok := f.Interface().(encoding.TextMarshaler); ok {
-			b, err := cm.MarshalText()
+	if err := beforeMarshal(m.record); err != nil {
+		return err
+	}
+
+	if m.generated != nil {
+		if err := m.generated.MarshalCSVRow(m.row); err != nil {
+			return fmt.Errorf("cannot marshal: %w", err)
+		}
+	} else {
+		for i, fd := range m.codec.fields {
+			s, err := m.codec.encoders[i](m.value.FieldByIndex(fd.index))
 			if err != nil {
 				return fmt.Errorf("cannot marshal: %w", err)
 			}
-			row[ci] = string(b)
-			continue
+			m.row[i] = s
 		}
+	}
+
+	if m.codec.extra != nil {
+		if err := m.marshalExtra(); err != nil {
+			return err
+		}
+	}
 
-		switch f.Kind() {
-		case reflect.String:
-			row[ci] = fmt.Sprintf("%s", f.String())
-		case reflect.Int:
-			row[ci] = fmt.Sprintf("%d", f.Int())
-		case reflect.Bool:
-			row[ci] = fmt.Sprintf("%t", f.Bool())
-		case reflect.Float64:
-			row[ci] = fmt.Sprintf("%s", strconv.FormatFloat(f.Float(), 'f', -1, 64))
+	row := m.row
+	if m.columns != nil {
+		for i, j := range m.columns {
+			m.out[i] = m.row[j]
 		}
+		row = m.out
 	}
 
 	if err := m.writer.Write(row); err != nil {
@@ -72,6 +223,49 @@ func (m *CSVMarshaler[T]) Marshal(record T) error {
 	return nil
 }
 
+// marshalExtra fills the extra columns of the row. Values for columns not in
+// the header are an error, unless they are empty.
+func (m *CSVMarshaler[T]) marshalExtra() error {
+	for _, i := range m.extra {
+		m.row[i] = ""
+	}
+
+	for name, s := range m.value.FieldByIndex(m.codec.extra).Interface().(map[string]string) {
+		i, ok := m.extra[name]
+		if !ok {
+			if s == "" {
+				continue
+			}
+			return fmt.Errorf("cannot marshal: extra column %q is not in the header", name)
+		}
+		m.row[i] = s
+	}
+
+	return nil
+}
+
+// WriteAll marshals every record and flushes the writer. With
+// ContinueOnError, records that cannot be marshaled are skipped and their
+// errors joined.
+func (m *CSVMarshaler[T]) WriteAll(records iter.Seq[T]) error {
+	var errs []error
+
+	for r := range records {
+		if err := m.Marshal(r); err != nil {
+			errs = append(errs, err)
+			if !m.opts.continueOnError || m.writer.Error() != nil {
+				break
+			}
+		}
+	}
+
+	if err := m.Flush(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
 func (m *CSVMarshaler[T]) Flush() error {
 	m.writer.Flush()
 
@@ -3,7 +3,9 @@ package csvmum
 import (
 	"bytes"
 	"encoding/csv"
+	"slices"
 	"testing"
+	"time"
 	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
@@ -257,7 +259,7 @@ func TestMarshal(t *testing.T) {
 		b := &bytes.Buffer{}
 		m, _ := NewMarshaler[testType](b)
 
-		m.writer.Comma = utf8.RuneError
+		m.writer.(*csv.Writer).Comma = utf8.RuneError
 
 		err := m.Marshal(testType{"one", 1})
 		assert.EqualError(err, "cannot marshal: csv: invalid field or comment delimiter")
@@ -265,4 +267,453 @@ func TestMarshal(t *testing.T) {
 		m.Flush()
 		assert.Equal([]byte("First,Second\n"), b.Bytes())
 	})
+	t.Run("all kinds", func(t *testing.T) {
+		t.Parallel()
+
+		assert := assert.New(t)
+
+		type testType struct {
+			Int8     int8
+			Int64    int64
+			Uint     uint
+			Uint16   uint16
+			Float32  float32
+			Duration time.Duration
+		}
+
+		b := &bytes.Buffer{}
+		m, _ := NewMarshaler[testType](b)
+
+		err := m.Marshal(testType{-8, -64, 1, 16, 3.1, 90 * time.Second})
+		assert.Nil(err)
+
+		m.Flush()
+		assert.Equal([]byte("Int8,Int64,Uint,Uint16,Float32,Duration\n-8,-64,1,16,3.1,1m30s\n"), b.Bytes())
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		t.Parallel()
+
+		assert := assert.New(t)
+
+		type testType struct {
+			String *string
+			Int    *int
+			Field  *customMarshalAndUnmarshal
+		}
+
+		s, i := "one", 2
+
+		b := &bytes.Buffer{}
+		m, _ := NewMarshaler[testType](b)
+
+		err := m.Marshal(testType{&s, &i, &customMarshalAndUnmarshal{One: "three"}})
+		assert.Nil(err)
+
+		err = m.Marshal(testType{})
+		assert.Nil(err)
+
+		m.Flush()
+		assert.Equal([]byte("String,Int,Field\none,2,~three~\n,,\n"), b.Bytes())
+	})
+
+	t.Run("pointer receiver text marshaler", func(t *testing.T) {
+		t.Parallel()
+
+		assert := assert.New(t)
+
+		type testType struct {
+			Field pointerMarshaler
+		}
+
+		b := &bytes.Buffer{}
+		m, _ := NewMarshaler[testType](b)
+
+		err := m.Marshal(testType{Field: pointerMarshaler{One: "one"}})
+		assert.Nil(err)
+
+		m.Flush()
+		assert.Equal([]byte("Field\n<one>\n"), b.Bytes())
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		t.Parallel()
+
+		assert := assert.New(t)
+
+		type testType struct {
+			Map map[string]string
+		}
+
+		b := &bytes.Buffer{}
+		_, err := NewMarshaler[testType](b)
+
+		assert.EqualError(err, "cannot marshal: field Map: unsupported type: map[string]string")
+		assert.Empty(b.String())
+	})
+	t.Run("omitempty", func(t *testing.T) {
+		t.Parallel()
+
+		assert := assert.New(t)
+
+		type testType struct {
+			First  string  `csv:"first,omitempty"`
+			Second int     `csv:"second,omitempty"`
+			Third  float64 `csv:"third"`
+			Fourth bool    `csv:"fourth,omitempty"`
+		}
+
+		b := &bytes.Buffer{}
+		m, _ := NewMarshaler[testType](b)
+
+		err := m.Marshal(testType{"one", 2, 3, true})
+		assert.Nil(err)
+
+		err = m.Marshal(testType{})
+		assert.Nil(err)
+
+		m.Flush()
+		assert.Equal([]byte("first,second,third,fourth\none,2,3,true\n,,0,\n"), b.Bytes())
+	})
+}
+
+func TestWriteAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("simple", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			First  string `csv:"first"`
+			Second int    `csv:"second"`
+		}
+
+		b := &bytes.Buffer{}
+		m, _ := NewMarshaler[testType](b)
+
+		err := m.WriteAll(slices.Values([]testType{{"one", 1}, {"two", 2}}))
+
+		assert.Nil(err)
+		assert.Equal([]byte("first,second\none,1\ntwo,2\n"), b.Bytes())
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			Field customMarshalAndUnmarshal
+		}
+
+		b := &bytes.Buffer{}
+		m, _ := NewMarshaler[testType](b)
+
+		err := m.WriteAll(slices.Values([]testType{{customMarshalAndUnmarshal{"one"}}, {}, {customMarshalAndUnmarshal{"three"}}}))
+
+		assert.EqualError(err, "cannot marshal: invalid text: ")
+		assert.Equal([]byte("Field\n~one~\n"), b.Bytes())
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			Field customMarshalAndUnmarshal
+		}
+
+		b := &bytes.Buffer{}
+		m, _ := NewMarshaler[testType](b, ContinueOnError())
+
+		err := m.WriteAll(slices.Values([]testType{{}, {customMarshalAndUnmarshal{"two"}}, {}}))
+
+		assert.EqualError(err, "cannot marshal: invalid text: \ncannot marshal: invalid text: ")
+		assert.Equal([]byte("Field\n~two~\n"), b.Bytes())
+	})
+
+	t.Run("closed writer", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			First string
+		}
+
+		b := &closeReaderWriter{}
+		m, _ := NewMarshaler[testType](b, ContinueOnError())
+
+		b.Close()
+
+		err := m.WriteAll(slices.Values([]testType{{"one"}, {"two"}}))
+
+		assert.EqualError(err, "cannot marshal: closed")
+	})
+}
+
+func TestMarshalExtra(t *testing.T) {
+	t.Parallel()
+
+	type testType struct {
+		First  string            `csv:"first"`
+		Second int               `csv:"second"`
+		Extra  map[string]string `csv:",extra"`
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		input := "first,vendor_b,second,vendor_a\none,b1,1,a1\ntwo,,2,a2\n"
+
+		um, err := NewUnmarshaler[testType](bytes.NewBufferString(input))
+		assert.NoError(err)
+
+		b := &bytes.Buffer{}
+		m, err := NewMarshaler[testType](b, WithExtraColumns(um.ExtraColumns()...))
+		assert.NoError(err)
+
+		assert.NoError(m.WriteAll(func(yield func(testType) bool) {
+			for r, err := range um.All() {
+				assert.NoError(err)
+				if !yield(r) {
+					return
+				}
+			}
+		}))
+		assert.Equal("first,second,vendor_b,vendor_a\none,1,b1,a1\ntwo,2,,a2\n", b.String())
+	})
+
+	t.Run("missing values", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := &bytes.Buffer{}
+		m, err := NewMarshaler[testType](b, WithExtraColumns("vendor_a", "vendor_b"))
+		assert.NoError(err)
+
+		assert.NoError(m.Marshal(testType{First: "one", Extra: map[string]string{"vendor_b": "b1"}}))
+		assert.NoError(m.Marshal(testType{First: "two"}))
+		m.Flush()
+
+		assert.Equal("first,second,vendor_a,vendor_b\none,0,,b1\ntwo,0,,\n", b.String())
+	})
+
+	t.Run("column not in header", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := &bytes.Buffer{}
+		m, err := NewMarshaler[testType](b)
+		assert.NoError(err)
+
+		assert.NoError(m.Marshal(testType{First: "one", Extra: map[string]string{"vendor_a": ""}}))
+		assert.EqualError(m.Marshal(testType{First: "two", Extra: map[string]string{"vendor_a": "a2"}}),
+			`cannot marshal: extra column "vendor_a" is not in the header`)
+		m.Flush()
+
+		assert.Equal("first,second\none,0\n", b.String())
+	})
+
+	t.Run("duplicate column", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		_, err := NewMarshaler[testType](&bytes.Buffer{}, WithExtraColumns("vendor_a", "second"))
+		assert.EqualError(err, "cannot marshal: duplicate column: second")
+	})
+
+	t.Run("no extra field", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type plain struct {
+			First string `csv:"first"`
+		}
+
+		_, err := NewMarshaler[plain](&bytes.Buffer{}, WithExtraColumns("vendor_a"))
+		assert.EqualError(err, "cannot marshal: extra columns given for csvmum.plain, which has no extra field")
+	})
+}
+
+func TestMarshalColumns(t *testing.T) {
+	t.Parallel()
+
+	type testType struct {
+		First  string            `csv:"first"`
+		Second int               `csv:"second"`
+		Third  bool              `csv:"third"`
+		Extra  map[string]string `csv:",extra"`
+	}
+
+	type ordered struct {
+		First  string `csv:"first"`
+		Second int    `csv:"second,order=2"`
+		Third  bool   `csv:"third"`
+		Fourth string `csv:"fourth,order=1"`
+	}
+
+	record := testType{First: "one", Second: 1, Third: true, Extra: map[string]string{"vendor_a": "a1"}}
+
+	for _, tc := range []struct {
+		name     string
+		marshal  func(*bytes.Buffer) error
+		expected string
+		err      string
+	}{{
+		name: "columns",
+		marshal: func(b *bytes.Buffer) error {
+			return marshalOne(b, testType{First: "one", Third: true}, WithColumns("third", "first"))
+		},
+		expected: "third,first\ntrue,one\n",
+	}, {
+		name: "columns and extra columns",
+		marshal: func(b *bytes.Buffer) error {
+			return marshalOne(b, record, WithColumns("second", "first"), WithExtraColumns("vendor_a"))
+		},
+		expected: "second,first,vendor_a\n1,one,a1\n",
+	}, {
+		name: "order tag",
+		marshal: func(b *bytes.Buffer) error {
+			return marshalOne(b, ordered{First: "one", Second: 2, Third: true, Fourth: "four"})
+		},
+		expected: "fourth,second,first,third\nfour,2,one,true\n",
+	}, {
+		name: "columns override order tag",
+		marshal: func(b *bytes.Buffer) error {
+			return marshalOne(b, ordered{First: "one", Fourth: "four"}, WithColumns("first", "fourth"))
+		},
+		expected: "first,fourth\none,four\n",
+	}, {
+		name: "unknown column",
+		marshal: func(b *bytes.Buffer) error {
+			return marshalOne(b, record, WithColumns("first", "vendor_a"))
+		},
+		err: "cannot marshal: unknown column: vendor_a",
+	}, {
+		name: "duplicate column",
+		marshal: func(b *bytes.Buffer) error {
+			return marshalOne(b, record, WithColumns("first", "first"))
+		},
+		err: "cannot marshal: duplicate column: first",
+	}, {
+		name: "invalid order",
+		marshal: func(b *bytes.Buffer) error {
+			type invalid struct {
+				First string `csv:"first,order=one"`
+			}
+			return marshalOne(b, invalid{})
+		},
+		err: `cannot marshal: invalid order for first: "one" is not an integer`,
+	}} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			b := &bytes.Buffer{}
+			err := tc.marshal(b)
+			if tc.err != "" {
+				assert.EqualError(err, tc.err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.expected, b.String())
+		})
+	}
+}
+
+func TestMarshalDialect(t *testing.T) {
+	t.Parallel()
+
+	type testType struct {
+		Name  string `csv:"name"`
+		Count int    `csv:"count"`
+	}
+
+	records := []testType{{Name: "one", Count: 1}, {Name: "a \"quoted\"\nname, with\ttab"}}
+
+	for _, tc := range []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{{
+		name:     "default",
+		expected: "name,count\none,1\n\"a \"\"quoted\"\"\nname, with\ttab\",0\n",
+	}, {
+		name:     "without header",
+		opts:     []Option{WithoutHeader()},
+		expected: "one,1\n\"a \"\"quoted\"\"\nname, with\ttab\",0\n",
+	}, {
+		name:     "tsv",
+		opts:     []Option{TSV()},
+		expected: "name\tcount\none\t1\n\"a \"\"quoted\"\"\nname, with\ttab\"\t0\n",
+	}, {
+		name:     "semicolon",
+		opts:     []Option{WithDelimiter(';')},
+		expected: "name;count\none;1\n\"a \"\"quoted\"\"\nname, with\ttab\";0\n",
+	}, {
+		name:     "crlf",
+		opts:     []Option{UseCRLF()},
+		expected: "name,count\r\none,1\r\n\"a \"\"quoted\"\"\r\nname, with\ttab\",0\r\n",
+	}, {
+		name:     "quote all",
+		opts:     []Option{QuoteAll()},
+		expected: "\"name\",\"count\"\n\"one\",\"1\"\n\"a \"\"quoted\"\"\nname, with\ttab\",\"0\"\n",
+	}, {
+		name:     "quote all tsv crlf",
+		opts:     []Option{QuoteAll(), TSV(), UseCRLF()},
+		expected: "\"name\"\t\"count\"\r\n\"one\"\t\"1\"\r\n\"a \"\"quoted\"\"\r\nname, with\ttab\"\t\"0\"\r\n",
+	}} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			b := &bytes.Buffer{}
+			m, err := NewMarshaler[testType](b, tc.opts...)
+			assert.NoError(err)
+			assert.NoError(m.WriteAll(slices.Values(records)))
+
+			assert.Equal(tc.expected, b.String())
+		})
+	}
+
+	t.Run("quote all with csv.Writer", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCSVMarshaler[testType](csv.NewWriter(&bytes.Buffer{}), QuoteAll())
+		assert.EqualError(t, err, "cannot marshal: QuoteAll requires NewMarshaler")
+	})
+
+	t.Run("invalid delimiter", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewMarshaler[testType](&bytes.Buffer{}, WithDelimiter('"'), QuoteAll())
+		assert.EqualError(t, err, `cannot marshal: invalid delimiter '"'`)
+	})
+
+	t.Run("closed writer", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := &closeReaderWriter{}
+		m, err := NewMarshaler[testType](b, QuoteAll())
+		assert.NoError(err)
+		b.Close()
+
+		m.Marshal(testType{Name: "one"})
+		assert.EqualError(m.Flush(), "cannot marshal: closed")
+	})
+}
+
+func marshalOne[T any](b *bytes.Buffer, record T, opts ...Option) error {
+	m, err := NewMarshaler[T](b, opts...)
+	if err != nil {
+		return err
+	}
+	if err := m.Marshal(record); err != nil {
+		return err
+	}
+	return m.Flush()
 }
@@ -0,0 +1,127 @@
+package csvmum
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Encoding is the character encoding of the input. Cells in other encodings
+// are transcoded to UTF-8 before they are unmarshaled.
+type Encoding int
+
+const (
+	UTF8 Encoding = iota
+	// Latin1 is ISO-8859-1.
+	Latin1
+	// Windows1252 is the Windows Western European code page, a superset of
+	// the printable characters of Latin1.
+	Windows1252
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case UTF8:
+		return "UTF-8"
+	case Latin1:
+		return "ISO-8859-1"
+	case Windows1252:
+		return "windows-1252"
+	}
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+// windows1252 holds the characters for bytes 0x80 to 0x9F. Bytes undefined in
+// windows-1252 keep their Latin1 meaning, as in the WHATWG encoding standard.
+var windows1252 = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡',
+	'ˆ', '‰', 'Š', '‹', 'Œ', '\u008d', 'Ž', '\u008f',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—',
+	'˜', '™', 'š', '›', 'œ', '\u009d', 'ž', 'Ÿ',
+}
+
+func (e Encoding) decode(s string) string {
+	if e == UTF8 || isASCII(s) {
+		return s
+	}
+
+	b := strings.Builder{}
+	b.Grow(len(s) + len(s)/2)
+	for i := range len(s) {
+		c := s[i]
+		r := rune(c)
+		if e == Windows1252 && c >= 0x80 && c < 0xa0 {
+			r = windows1252[c-0x80]
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isASCII(s string) bool {
+	for i := range len(s) {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+const bom = "\ufeff"
+
+// normalizeRecord applies the input options to the cells of r in place.
+func (o options) normalizeRecord(r []string) {
+	if o.encoding == UTF8 && !o.trimSpace {
+		return
+	}
+
+	for i, s := range r {
+		s = o.encoding.decode(s)
+		if o.trimSpace {
+			s = strings.TrimSpace(s)
+		}
+		r[i] = s
+	}
+}
+
+// stripBOMReader returns r without a byte order mark at its start, and the
+// number of bytes removed. The mark has to go before the csv package reads
+// the header, or a quoted first column would be a parse error.
+func (o options) stripBOMReader(r io.Reader) (io.Reader, int64) {
+	if !o.stripBOM {
+		return r, 0
+	}
+
+	br := bufio.NewReader(r)
+	if b, _ := br.Peek(len(bom)); string(b) == bom {
+		br.Discard(len(bom))
+		return br, int64(len(bom))
+	}
+	return br, 0
+}
+
+// normalizeHeaderRecord is normalizeRecord for the header, which is also where
+// a byte order mark would be, if the input was given as a *csv.Reader.
+func (o options) normalizeHeaderRecord(hh []string) {
+	if o.stripBOM && len(hh) > 0 {
+		hh[0] = strings.TrimPrefix(hh[0], bom)
+	}
+	o.normalizeRecord(hh)
+}
+
+// configureReader applies the options of o that are settings of csv.Reader.
+func (o options) configureReader(r *csv.Reader) error {
+	if o.lazyQuotes {
+		r.LazyQuotes = true
+	}
+	if o.comma != 0 {
+		if !validDelim(o.comma) {
+			return fmt.Errorf("invalid delimiter %q", o.comma)
+		}
+		r.Comma = o.comma
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package csvmum
+
+import (
+	"os"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	type agency struct {
+		ID   string `csv:"agency_id"`
+		Name string `csv:"agency_name"`
+	}
+
+	tt := []struct {
+		name     string
+		file     string
+		opts     []Option
+		expected []agency
+		err      string
+	}{{
+		name:     "bom",
+		file:     "testdata/bom.csv",
+		opts:     []Option{StripBOM()},
+		expected: []agency{{ID: "A1", Name: "Metro"}},
+	}, {
+		name:     "bom not stripped",
+		file:     "testdata/bom.csv",
+		expected: []agency{{Name: "Metro"}},
+	}, {
+		name:     "bom before quoted header",
+		file:     "testdata/bomquoted.csv",
+		opts:     []Option{StripBOM()},
+		expected: []agency{{ID: "A1", Name: "Metro"}, {ID: "A2", Name: "Bus"}},
+	}, {
+		name: "windows-1252",
+		file: "testdata/windows1252.csv",
+		opts: []Option{WithEncoding(Windows1252)},
+		expected: []agency{
+			{ID: "A1", Name: "Société de transport – “Métro”"},
+			{ID: "A2", Name: "Zürich € Œuvre"},
+		},
+	}, {
+		name: "latin-1",
+		file: "testdata/latin1.csv",
+		opts: []Option{WithEncoding(Latin1)},
+		expected: []agency{
+			{ID: "A1", Name: "Société de transport"},
+			{ID: "A2", Name: "Zürich ½"},
+		},
+	}, {
+		name: "padded",
+		file: "testdata/padded.csv",
+		opts: []Option{TrimSpace()},
+		expected: []agency{
+			{ID: "A1", Name: "Metro"},
+			{ID: "A2", Name: "Bus"},
+		},
+	}, {
+		name:     "padded not trimmed",
+		file:     "testdata/padded.csv",
+		expected: []agency{{}, {}},
+	}, {
+		name: "lazy quotes",
+		file: "testdata/lazyquotes.csv",
+		opts: []Option{LazyQuotes()},
+		expected: []agency{
+			{ID: "A1", Name: `The "Metro"`},
+			{ID: "A2", Name: `Bus "Express" Line`},
+		},
+	}, {
+		name:     "strict quotes",
+		file:     "testdata/lazyquotes.csv",
+		expected: []agency{},
+		err:      `cannot unmarshal: parse error on line 2, column 8: bare " in non-quoted-field`,
+	}, {
+		name: "tsv",
+		file: "testdata/agency.tsv",
+		opts: []Option{TSV()},
+		expected: []agency{
+			{ID: "A1", Name: "Metro, Rail"},
+			{ID: "A2", Name: "Bus\tExpress"},
+		},
+	}, {
+		name:     "invalid delimiter",
+		file:     "testdata/agency.tsv",
+		opts:     []Option{WithDelimiter('\n')},
+		expected: []agency(nil),
+		err:      `cannot unmarshal: invalid delimiter '\n'`,
+	}}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			f, err := os.Open(tc.file)
+			if !assert.NoError(err) {
+				return
+			}
+			defer f.Close()
+
+			um, err := NewUnmarshaler[agency](f, tc.opts...)
+			if tc.expected == nil {
+				assert.EqualError(err, tc.err)
+				return
+			}
+			assert.NoError(err)
+
+			records, err := um.ReadAll()
+			if tc.err != "" {
+				assert.EqualError(err, tc.err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(tc.expected, records)
+		})
+	}
+}
+
+func TestEncodingDecode(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	all := make([]byte, 0, 128)
+	for c := 0x80; c < 0x100; c++ {
+		all = append(all, byte(c))
+	}
+
+	assert.Equal("plain ascii", Windows1252.decode("plain ascii"))
+	assert.Equal("caf\xe9", UTF8.decode("caf\xe9"))
+	assert.Equal("café", Latin1.decode("caf\xe9"))
+	assert.Equal("€\u0081‚ƒ„…†‡ˆ‰Š‹Œ\u008dŽ\u008f\u0090‘’“”•–—˜™š›œ\u009džŸ", Windows1252.decode(string(all[:32])))
+
+	assert.Equal(128, utf8.RuneCountInString(Latin1.decode(string(all))))
+	assert.Equal(string([]rune{0xa0, 0xff}), Windows1252.decode("\xa0\xff"))
+}
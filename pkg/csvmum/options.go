@@ -0,0 +1,177 @@
+package csvmum
+
+type HeaderPolicy int
+
+const (
+	// HeaderLenient maps columns by exact name and ignores everything else.
+	HeaderLenient HeaderPolicy = iota
+	// HeaderWarn maps columns by exact name too, and records diagnostics for
+	// the header, including columns differing from a field name only by case
+	// or surrounding whitespace.
+	HeaderWarn
+	// HeaderStrict behaves like HeaderWarn, but any diagnostic is an error.
+	HeaderStrict
+)
+
+type options struct {
+	headerPolicy     HeaderPolicy
+	continueOnError  bool
+	disableGenerated bool
+	extraColumns     []string
+	stripBOM         bool
+	encoding         Encoding
+	trimSpace        bool
+	lazyQuotes       bool
+	columns          []string
+	noHeader         bool
+	comma            rune
+	useCRLF          bool
+	quoteAll         bool
+	sortBuffer       int
+	workers          int
+	unordered        bool
+}
+
+type Option func(*options)
+
+func buildOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func WithHeaderPolicy(p HeaderPolicy) Option {
+	return func(o *options) {
+		o.headerPolicy = p
+	}
+}
+
+// ContinueOnError makes All, ReadAll and WriteAll skip records that cannot be
+// unmarshaled or marshaled instead of stopping at the first one. Errors from
+// the underlying reader or writer still stop them.
+func ContinueOnError() Option {
+	return func(o *options) {
+		o.continueOnError = true
+	}
+}
+
+// DisableGenerated makes the unmarshaler and marshaler use reflection even if
+// T has methods generated by csvmumgen.
+func DisableGenerated() Option {
+	return func(o *options) {
+		o.disableGenerated = true
+	}
+}
+
+// WithExtraColumns sets the columns a marshaler writes, in order, after the
+// struct's own columns, from the map in the struct's extra field. Use
+// CSVUnmarshaler.ExtraColumns to keep the columns of a file being rewritten.
+func WithExtraColumns(names ...string) Option {
+	return func(o *options) {
+		o.extraColumns = names
+	}
+}
+
+// StripBOM removes a UTF-8 byte order mark from the start of the input, which
+// would otherwise become part of the first column name. Given an io.Reader,
+// the mark is removed before the header is parsed, so the first column name
+// may be quoted.
+func StripBOM() Option {
+	return func(o *options) {
+		o.stripBOM = true
+	}
+}
+
+// WithEncoding transcodes the input from e to UTF-8.
+func WithEncoding(e Encoding) Option {
+	return func(o *options) {
+		o.encoding = e
+	}
+}
+
+// TrimSpace removes leading and trailing white space from every cell,
+// including the column names in the header.
+func TrimSpace() Option {
+	return func(o *options) {
+		o.trimSpace = true
+	}
+}
+
+// LazyQuotes sets LazyQuotes on the csv.Reader, so that quotes may appear in
+// unquoted cells and unescaped in quoted cells.
+func LazyQuotes() Option {
+	return func(o *options) {
+		o.lazyQuotes = true
+	}
+}
+
+// WithColumns sets the columns a marshaler writes, and their order, in place
+// of every column of the struct in struct order. Extra columns follow them.
+func WithColumns(names ...string) Option {
+	return func(o *options) {
+		o.columns = names
+	}
+}
+
+// WithoutHeader makes a marshaler write records without a header first, for
+// appending to a file that already has one.
+func WithoutHeader() Option {
+	return func(o *options) {
+		o.noHeader = true
+	}
+}
+
+// WithDelimiter sets the delimiter between cells, in place of a comma, for
+// both reading and writing.
+func WithDelimiter(r rune) Option {
+	return func(o *options) {
+		o.comma = r
+	}
+}
+
+// TSV reads and writes tab separated values. It is WithDelimiter('\t').
+func TSV() Option {
+	return WithDelimiter('\t')
+}
+
+// UseCRLF makes a marshaler end lines with \r\n instead of \n.
+func UseCRLF() Option {
+	return func(o *options) {
+		o.useCRLF = true
+	}
+}
+
+// QuoteAll makes a marshaler quote every cell, rather than only those that
+// need it. It requires NewMarshaler, since csv.Writer always quotes as little
+// as possible.
+func QuoteAll() Option {
+	return func(o *options) {
+		o.quoteAll = true
+	}
+}
+
+// WithSortBuffer sets the number of records of each input Diff sorts in
+// memory, beyond which it sorts with temporary files. The default is 100000.
+func WithSortBuffer(n int) Option {
+	return func(o *options) {
+		o.sortBuffer = n
+	}
+}
+
+// WithWorkers sets the number of goroutines CSVUnmarshaler.Parallel decodes
+// records with. The default is GOMAXPROCS.
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		o.workers = n
+	}
+}
+
+// Unordered makes CSVUnmarshaler.Parallel yield records as soon as they are
+// decoded, rather than in input order.
+func Unordered() Option {
+	return func(o *options) {
+		o.unordered = true
+	}
+}
@@ -0,0 +1,238 @@
+package csvmum
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// parallelBatchSize is the number of records read and decoded together by
+// Parallel.
+const parallelBatchSize = 256
+
+// parallelBatch is a run of records read together, and then their results.
+type parallelBatch[T any] struct {
+	seq     int
+	records [][]string
+	lines   []recordLines
+	values  []T
+	errs    []error
+
+	// err is an error reading the input after the records, if any
+	err error
+}
+
+// recordLines holds the lines of the cells of a record, which must be taken
+// from the csv.Reader as each record is read.
+type recordLines struct {
+	start int
+	// cells is nil if every cell is on the start line
+	cells []int
+}
+
+func (l recordLines) line(i int) int {
+	if l.cells == nil {
+		return l.start
+	}
+	return l.cells[i]
+}
+
+// Parallel returns an iterator over the remaining records, like All, but with
+// records decoded by a pool of goroutines while one goroutine reads them. The
+// pool has GOMAXPROCS goroutines unless WithWorkers is given.
+//
+// Records are yielded in input order, unless the unmarshaler was created with
+// Unordered, in which case they are yielded as soon as they are decoded. At
+// most a few batches of records per worker are held in memory at once, so a
+// slow consumer holds back reading.
+//
+// Like All, the iterator stops after the first error unless the unmarshaler
+// was created with ContinueOnError. Records read ahead are discarded if
+// iteration stops early, and the unmarshaler must not be used by anything else
+// until the iterator has returned.
+func (um *CSVUnmarshaler[T]) Parallel() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		workers := um.opts.workers
+		if workers <= 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+
+		jobs := make(chan *parallelBatch[T])
+		results := make(chan *parallelBatch[T])
+		// tokens bounds the batches between the reader and yield
+		tokens := make(chan struct{}, 2*workers)
+		done := make(chan struct{})
+
+		var wg sync.WaitGroup
+		defer func() {
+			close(done)
+			wg.Wait()
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			um.readBatches(jobs, tokens, done)
+		}()
+
+		var decoders sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			decoders.Add(1)
+			go func() {
+				defer wg.Done()
+				defer decoders.Done()
+				for b := range jobs {
+					um.decodeBatch(b)
+					select {
+					case results <- b:
+					case <-done:
+						return
+					}
+				}
+			}()
+		}
+		go func() {
+			decoders.Wait()
+			close(results)
+		}()
+
+		next := 0
+		pending := map[int]*parallelBatch[T]{}
+		for b := range results {
+			if um.opts.unordered {
+				if !um.yieldBatch(b, yield) {
+					return
+				}
+				<-tokens
+				continue
+			}
+
+			pending[b.seq] = b
+			for {
+				b, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+
+				if !um.yieldBatch(b, yield) {
+					return
+				}
+				<-tokens
+			}
+		}
+	}
+}
+
+// readBatches reads batches of records into jobs until the end of the input,
+// an error that stops iteration, or done is closed.
+func (um *CSVUnmarshaler[T]) readBatches(jobs chan<- *parallelBatch[T], tokens chan<- struct{}, done <-chan struct{}) {
+	defer close(jobs)
+
+	for seq := 0; ; seq++ {
+		select {
+		case tokens <- struct{}{}:
+		case <-done:
+			return
+		}
+
+		b := &parallelBatch[T]{seq: seq}
+		more := true
+		for more && len(b.records) < parallelBatchSize {
+			r, err := um.reader.Read()
+			if err == io.EOF {
+				more = false
+				break
+			}
+			if err != nil {
+				b.err = fmt.Errorf("cannot unmarshal: %w", err)
+				more = um.opts.continueOnError && isRecordError(b.err)
+				break
+			}
+
+			// the csv.Reader may reuse r
+			r = slices.Clone(r)
+			b.records = append(b.records, r)
+			b.lines = append(b.lines, um.recordLines(len(r)))
+		}
+
+		select {
+		case jobs <- b:
+		case <-done:
+			return
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+func (um *CSVUnmarshaler[T]) recordLines(n int) recordLines {
+	start, _ := um.reader.FieldPos(0)
+	l := recordLines{start: start + um.lineOffset}
+
+	if last, _ := um.reader.FieldPos(max(n-1, 0)); last != start {
+		l.cells = make([]int, n)
+		for i := range n {
+			line, _ := um.reader.FieldPos(i)
+			l.cells[i] = line + um.lineOffset
+		}
+	}
+
+	return l
+}
+
+func (um *CSVUnmarshaler[T]) decodeBatch(b *parallelBatch[T]) {
+	b.values = make([]T, len(b.records))
+	b.errs = make([]error, len(b.records))
+
+	for k, r := range b.records {
+		um.opts.normalizeRecord(r)
+
+		record := &b.values[k]
+		var generated RowUnmarshaler
+		if um.generated != nil {
+			generated = any(record).(RowUnmarshaler)
+		}
+
+		if err := um.decode(reflect.ValueOf(record).Elem(), generated, r, b.lines[k].line); err != nil {
+			var zero T
+			*record = zero
+			b.errs[k] = err
+		}
+	}
+
+	// the records are no longer needed once decoded
+	b.records = nil
+	b.lines = nil
+}
+
+// yieldBatch yields the results of b, and reports whether iteration should
+// go on.
+func (um *CSVUnmarshaler[T]) yieldBatch(b *parallelBatch[T], yield func(T, error) bool) bool {
+	for k, v := range b.values {
+		err := b.errs[k]
+		if !yield(v, err) {
+			return false
+		}
+		if err != nil && !(um.opts.continueOnError && isRecordError(err)) {
+			return false
+		}
+	}
+
+	if b.err != nil {
+		var zero T
+		if !yield(zero, b.err) {
+			return false
+		}
+		return um.opts.continueOnError && isRecordError(b.err)
+	}
+
+	return true
+}
@@ -0,0 +1,196 @@
+package csvmum
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parallelType struct {
+	Name  string `csv:"name"`
+	Value int    `csv:"value"`
+}
+
+// parallelInput returns a file of n records of parallelType, one per line.
+func parallelInput(n int) string {
+	var b strings.Builder
+	b.WriteString("name,value\n")
+	for i := range n {
+		fmt.Fprintf(&b, "r%d,%d\n", i, i)
+	}
+	return b.String()
+}
+
+func TestParallel(t *testing.T) {
+	t.Parallel()
+
+	// enough records for several batches per worker
+	const n = 5*parallelBatchSize + 7
+
+	want := make([]parallelType, n)
+	for i := range want {
+		want[i] = parallelType{fmt.Sprintf("r%d", i), i}
+	}
+
+	testCases := map[string]struct {
+		opts []Option
+	}{
+		"default":   {},
+		"1 worker":  {opts: []Option{WithWorkers(1)}},
+		"4 workers": {opts: []Option{WithWorkers(4)}},
+		"reflect":   {opts: []Option{WithWorkers(4), DisableGenerated()}},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			m, err := NewUnmarshaler[parallelType](strings.NewReader(parallelInput(n)), tc.opts...)
+			assert.Nil(err)
+
+			records := []parallelType{}
+			for r, err := range m.Parallel() {
+				assert.Nil(err)
+				records = append(records, r)
+			}
+
+			assert.Equal(want, records)
+		})
+	}
+
+	t.Run("unordered", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		m, _ := NewUnmarshaler[parallelType](strings.NewReader(parallelInput(n)), WithWorkers(4), Unordered())
+
+		records := []parallelType{}
+		for r, err := range m.Parallel() {
+			assert.Nil(err)
+			records = append(records, r)
+		}
+
+		slices.SortFunc(records, func(a, b parallelType) int { return a.Value - b.Value })
+		assert.Equal(want, records)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		m, _ := NewUnmarshaler[parallelType](strings.NewReader("name,value\n"))
+
+		n := 0
+		for range m.Parallel() {
+			n++
+		}
+
+		assert.Equal(0, n)
+	})
+
+	t.Run("break", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		m, _ := NewUnmarshaler[parallelType](strings.NewReader(parallelInput(n)), WithWorkers(2))
+
+		records := []parallelType{}
+		for r, err := range m.Parallel() {
+			assert.Nil(err)
+			records = append(records, r)
+			if len(records) == 3 {
+				break
+			}
+		}
+
+		assert.Equal(want[:3], records)
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("name,value\none,1\ntwo,two\nthree,3\n")
+		m, _ := NewUnmarshaler[parallelType](b, WithWorkers(2))
+
+		records := []parallelType{}
+		errs := []error{}
+		for r, err := range m.Parallel() {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			records = append(records, r)
+		}
+
+		assert.Equal([]parallelType{{"one", 1}}, records)
+		assert.Len(errs, 1)
+		assert.EqualError(errs[0], `cannot unmarshal line 3, column "value", field Value, value "two": error parsing int: strconv.ParseInt: parsing "two": invalid syntax`)
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("name,value\none,1\ntwo,two\nthree,3,extra\nfour,4\n")
+		m, _ := NewUnmarshaler[parallelType](b, WithWorkers(2), ContinueOnError())
+
+		records := []parallelType{}
+		errs := []error{}
+		for r, err := range m.Parallel() {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			records = append(records, r)
+		}
+
+		assert.Equal([]parallelType{{"one", 1}, {"four", 4}}, records)
+		assert.Len(errs, 2)
+
+		var pe *ParseError
+		assert.ErrorAs(errs[0], &pe)
+		assert.ErrorIs(errs[1], csv.ErrFieldCount)
+	})
+
+	t.Run("continue on error: reader error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := &closeReaderWriter{}
+		b.WriteString("name,value\n")
+		m, _ := NewUnmarshaler[parallelType](b, ContinueOnError())
+
+		b.Close()
+
+		n := 0
+		for _, err := range m.Parallel() {
+			assert.EqualError(err, "cannot unmarshal: closed")
+			n++
+		}
+
+		assert.Equal(1, n)
+	})
+
+	t.Run("multiline line numbers", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("name,value\n\"one\ntwo\",x\n")
+		m, _ := NewUnmarshaler[parallelType](b)
+
+		for _, err := range m.Parallel() {
+			var pe *ParseError
+			if assert.ErrorAs(err, &pe) {
+				assert.Equal(3, pe.Line)
+			}
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package csvmum
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// parser is a registered parse and format pair for one type. parse and
+// format hold the functions as registered, for Parse and Format.
+type parser struct {
+	typ    reflect.Type
+	decode decodeFunc
+	encode encodeFunc
+	parse  any
+	format any
+}
+
+var parsers = struct {
+	sync.RWMutex
+	byName map[string]*parser
+	byType map[reflect.Type]*parser
+}{
+	byName: map[string]*parser{},
+	byType: map[reflect.Type]*parser{},
+}
+
+// RegisterParser registers parse and format under name, for fields of type T
+// (or *T) tagged with parse=name. If format is nil, values are marshaled as
+// they would be without the parser.
+//
+// Parsers are not called for empty cells, which leave the field as its zero
+// value; use the required and default= options to handle them.
+//
+// RegisterParser is meant to be called from init functions. It panics if
+// parse is nil or name is already registered.
+func RegisterParser[T any](name string, parse func(string, *T) error, format func(T) (string, error)) {
+	if name == "" {
+		panic("csvmum: RegisterParser with empty name")
+	}
+
+	p := newParser(parse, format)
+
+	parsers.Lock()
+	defer parsers.Unlock()
+
+	if _, ok := parsers.byName[name]; ok {
+		panic(fmt.Sprintf("csvmum: parser %q already registered", name))
+	}
+	parsers.byName[name] = p
+}
+
+// RegisterTypeParser registers parse and format for every field of type T
+// (or *T) without a parse= option, in place of the built-in handling of T.
+// If format is nil, values are marshaled as they would be without the parser.
+//
+// Like RegisterParser, it is not called for empty cells, and panics if parse
+// is nil or T already has a parser.
+func RegisterTypeParser[T any](parse func(string, *T) error, format func(T) (string, error)) {
+	p := newParser(parse, format)
+
+	parsers.Lock()
+	defer parsers.Unlock()
+
+	if _, ok := parsers.byType[p.typ]; ok {
+		panic(fmt.Sprintf("csvmum: parser for %s already registered", p.typ))
+	}
+	parsers.byType[p.typ] = p
+
+	// codecs compiled before now use the built-in handling of T
+	codecs.Clear()
+}
+
+func newParser[T any](parse func(string, *T) error, format func(T) (string, error)) *parser {
+	if parse == nil {
+		panic("csvmum: nil parse function")
+	}
+
+	t := reflect.TypeFor[T]()
+	if format == nil {
+		// a parser for a type csvmum cannot encode only unmarshals
+		enc, err := compileEncoder(t)
+		format = func(v T) (string, error) {
+			if err != nil {
+				return "", err
+			}
+			return enc(reflect.ValueOf(&v).Elem())
+		}
+	}
+
+	return &parser{
+		typ: t,
+		decode: func(v reflect.Value, s string) error {
+			if s == "" {
+				return nil
+			}
+			return parse(s, v.Addr().Interface().(*T))
+		},
+		encode: func(v reflect.Value) (string, error) {
+			return format(v.Interface().(T))
+		},
+		parse:  parse,
+		format: format,
+	}
+}
+
+func namedParser(name string, t reflect.Type) (*parser, error) {
+	parsers.RLock()
+	p, ok := parsers.byName[name]
+	parsers.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown parser %q", name)
+	}
+	if p.typ != t {
+		return nil, fmt.Errorf("parser %q is for %s, not %s", name, p.typ, t)
+	}
+
+	return p, nil
+}
+
+func typeParser(t reflect.Type) (*parser, bool) {
+	parsers.RLock()
+	defer parsers.RUnlock()
+
+	p, ok := parsers.byType[t]
+	return p, ok
+}
+
+// hasTypeParser reports whether t, or the type it points to, has a parser
+// registered with RegisterTypeParser.
+func hasTypeParser(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	_, ok := typeParser(t)
+	return ok
+}
+
+// Parse parses s into v with the parser registered under name. It is used by
+// code generated by csvmumgen.
+func Parse[T any](name, s string, v *T) error {
+	p, err := namedParser(name, reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+	return p.parse.(func(string, *T) error)(s, v)
+}
+
+// Format formats v with the formatter registered under name. It is used by
+// code generated by csvmumgen.
+func Format[T any](name string, v T) (string, error) {
+	p, err := namedParser(name, reflect.TypeFor[T]())
+	if err != nil {
+		return "", err
+	}
+	return p.format.(func(T) (string, error))(v)
+}
@@ -0,0 +1,209 @@
+package csvmum
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type yesNo bool
+
+func init() {
+	RegisterParser("test.upper", func(s string, v *string) error {
+		*v = strings.ToUpper(s)
+		return nil
+	}, nil)
+
+	RegisterParser("test.hex", func(s string, v *int) error {
+		i, err := strconv.ParseInt(s, 16, 0)
+		if err != nil {
+			return fmt.Errorf("invalid hex: %s", s)
+		}
+		*v = int(i)
+		return nil
+	}, func(v int) (string, error) {
+		return strconv.FormatInt(int64(v), 16), nil
+	})
+
+	RegisterTypeParser(func(s string, v *yesNo) error {
+		switch s {
+		case "Y":
+			*v = true
+		case "N":
+			*v = false
+		default:
+			return fmt.Errorf("invalid yes/no: %s", s)
+		}
+		return nil
+	}, func(v yesNo) (string, error) {
+		if v {
+			return "Y", nil
+		}
+		return "N", nil
+	})
+}
+
+type parsed struct {
+	Name    string `csv:"name,parse=test.upper"`
+	Code    int    `csv:"code,parse=test.hex"`
+	Pointer *int   `csv:"pointer,parse=test.hex"`
+	Default int    `csv:"default,parse=test.hex,default=ff"`
+	Flag    yesNo  `csv:"flag"`
+	Flags   *yesNo `csv:"flags"`
+}
+
+func TestParser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unmarshal", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("name,code,pointer,default,flag,flags\none,1f,a,,Y,N\n,,,10,,\n")
+
+		um, err := NewUnmarshaler[parsed](b)
+		assert.NoError(err)
+
+		records, err := um.ReadAll()
+		assert.NoError(err)
+
+		ten := 10
+		no := yesNo(false)
+		assert.Equal([]parsed{
+			{Name: "ONE", Code: 31, Pointer: &ten, Default: 255, Flag: true, Flags: &no},
+			{Default: 16},
+		}, records)
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ten := 10
+		b := &bytes.Buffer{}
+		m, err := NewMarshaler[parsed](b)
+		assert.NoError(err)
+		assert.NoError(m.Marshal(parsed{Name: "one", Code: 31, Pointer: &ten, Default: 255, Flag: true}))
+		m.Flush()
+
+		assert.Equal("name,code,pointer,default,flag,flags\none,1f,a,ff,Y,\n", b.String())
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("code,flag\nxyz,Y\n1,maybe\n")
+
+		um, err := NewUnmarshaler[parsed](b, ContinueOnError())
+		assert.NoError(err)
+
+		_, err = um.ReadAll()
+		assert.EqualError(err, `cannot unmarshal line 2, column "code", field Code, value "xyz": invalid hex: xyz`+"\n"+
+			`cannot unmarshal line 3, column "flag", field Flag, value "maybe": invalid yes/no: maybe`)
+	})
+
+	t.Run("unknown parser", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type unknown struct {
+			Name string `csv:"name,parse=test.unknown"`
+		}
+
+		_, err := NewUnmarshaler[unknown](bytes.NewBufferString("name\n"))
+		assert.EqualError(err, `cannot unmarshal: invalid parser for name: unknown parser "test.unknown"`)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type wrong struct {
+			Name string `csv:"name,parse=test.hex"`
+		}
+
+		_, err := NewMarshaler[wrong](&bytes.Buffer{})
+		assert.EqualError(err, `cannot marshal: invalid parser for name: parser "test.hex" is for int, not string`)
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type invalid struct {
+			Code int `csv:"code,parse=test.hex,default=xyz"`
+		}
+
+		_, err := NewUnmarshaler[invalid](bytes.NewBufferString("code\n"))
+		assert.EqualError(err, "cannot unmarshal: invalid default for code: invalid hex: xyz")
+	})
+
+	t.Run("type parsers", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		c, err := codecFor(reflect.TypeOf(parsed{}))
+		assert.NoError(err)
+		assert.True(c.typeParsers)
+
+		c, err = codecFor(reflect.TypeOf(struct{ Name string }{}))
+		assert.NoError(err)
+		assert.False(c.typeParsers)
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	var i int
+	assert.NoError(Parse("test.hex", "ff", &i))
+	assert.Equal(255, i)
+	assert.EqualError(Parse("test.hex", "xyz", &i), "invalid hex: xyz")
+
+	var s string
+	assert.EqualError(Parse("test.hex", "ff", &s), `parser "test.hex" is for int, not string`)
+	assert.EqualError(Parse("test.unknown", "ff", &s), `unknown parser "test.unknown"`)
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s, err := Format("test.hex", 255)
+	assert.NoError(err)
+	assert.Equal("ff", s)
+
+	s, err = Format("test.upper", "one")
+	assert.NoError(err)
+	assert.Equal("one", s)
+
+	_, err = Format("test.unknown", 1)
+	assert.EqualError(err, `unknown parser "test.unknown"`)
+}
+
+func TestRegisterParser(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	parse := func(s string, v *string) error { return nil }
+
+	assert.PanicsWithValue(`csvmum: parser "test.upper" already registered`, func() {
+		RegisterParser("test.upper", parse, nil)
+	})
+	assert.PanicsWithValue("csvmum: RegisterParser with empty name", func() {
+		RegisterParser("", parse, nil)
+	})
+	assert.PanicsWithValue("csvmum: nil parse function", func() {
+		RegisterParser[string]("test.nil", nil, nil)
+	})
+	assert.PanicsWithValue("csvmum: parser for csvmum.yesNo already registered", func() {
+		RegisterTypeParser(func(s string, v *yesNo) error { return nil }, nil)
+	})
+}
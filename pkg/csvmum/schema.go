@@ -0,0 +1,297 @@
+package csvmum
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RecordSchema describes the CSV layout of a struct, as csvmum reads and
+// writes it.
+type RecordSchema struct {
+	// Type is the name of the struct type, as in gtfs.Stop.
+	Type string
+	// Columns are in the order a marshaler writes them.
+	Columns []Column
+	// Extra is set if the struct has an extra field for unmapped columns.
+	Extra bool
+}
+
+// Column describes a column of a RecordSchema.
+type Column struct {
+	Name string
+	// Field is the path to the struct field, as in Coords.Lat.
+	Field string
+	// Type is the Go type of the field.
+	Type       string
+	Required   bool
+	OmitEmpty  bool
+	HasDefault bool
+	Default    string
+	// Parser is the name from the parse= option, if any.
+	Parser string
+	// Separator separates the elements of slice fields, and is empty for
+	// others.
+	Separator string
+
+	typ reflect.Type
+}
+
+// Schema returns the schema of T, or the error NewUnmarshaler[T] or
+// NewMarshaler[T] would return for it.
+func Schema[T any]() (RecordSchema, error) {
+	t := reflect.TypeFor[T]()
+
+	c, err := codecFor(t)
+	if err != nil {
+		return RecordSchema{}, fmt.Errorf("cannot get schema: %w", err)
+	}
+	if err := cmp.Or(c.decodeErr, c.encodeErr); err != nil {
+		return RecordSchema{}, fmt.Errorf("cannot get schema: %w", err)
+	}
+
+	order, err := columnOrder(c.fields, nil)
+	if err != nil {
+		return RecordSchema{}, fmt.Errorf("cannot get schema: %w", err)
+	}
+	if order == nil {
+		order = make([]int, len(c.fields))
+		for i := range order {
+			order[i] = i
+		}
+	}
+
+	s := RecordSchema{Type: t.String(), Columns: make([]Column, len(order)), Extra: c.extra != nil}
+	for k, j := range order {
+		fd := c.fields[j]
+		ft := t.FieldByIndex(fd.index).Type
+
+		col := Column{
+			Name:       fd.name,
+			Field:      fd.goName(t),
+			Type:       ft.String(),
+			Required:   fd.required,
+			OmitEmpty:  fd.omitEmpty,
+			HasDefault: fd.hasDefault,
+			Default:    fd.defaultValue,
+			Parser:     fd.parser,
+			typ:        ft,
+		}
+		if isList(ft) {
+			col.Separator = fd.separator()
+		}
+		s.Columns[k] = col
+	}
+
+	return s, nil
+}
+
+// Header returns the column names, in order.
+func (s RecordSchema) Header() []string {
+	names := make([]string, len(s.Columns))
+	for i, col := range s.Columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// CSVTemplate returns the header line a marshaler writes, as a template for
+// files to be filled in by hand.
+func (s RecordSchema) CSVTemplate() string {
+	b := &bytes.Buffer{}
+	w := csv.NewWriter(b)
+	w.Write(s.Header())
+	w.Flush()
+	return b.String()
+}
+
+// Markdown returns a Markdown table of the columns.
+func (s RecordSchema) Markdown() string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "| Column | Field | Type | Required | Default | Notes |\n")
+	fmt.Fprintf(b, "|--------|-------|------|----------|---------|-------|\n")
+	for _, col := range s.Columns {
+		required := ""
+		if col.Required {
+			required = "Yes"
+		}
+		def := ""
+		if col.HasDefault {
+			def = markdownCode(col.Default)
+		}
+
+		var notes []string
+		if col.OmitEmpty {
+			notes = append(notes, "Empty when zero")
+		}
+		if col.Parser != "" {
+			notes = append(notes, "Parsed with "+markdownCode(col.Parser))
+		}
+		if col.Separator != "" {
+			notes = append(notes, "Separated by "+markdownCode(col.Separator))
+		}
+
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s | %s |\n",
+			markdownCode(col.Name), markdownCode(col.Field), markdownCode(col.Type), required, def, strings.Join(notes, ", "))
+	}
+
+	if s.Extra {
+		fmt.Fprintf(b, "\nOther columns are kept as extra columns.\n")
+	}
+
+	return b.String()
+}
+
+func markdownCode(s string) string {
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
+
+// JSONSchema returns a JSON Schema for a record as an object keyed by column
+// name, with values of the JSON types the columns' Go types correspond to.
+// Empty cells in columns of pointer type are null. Other properties are only
+// allowed if the struct has an extra field.
+func (s RecordSchema) JSONSchema() ([]byte, error) {
+	props := make(jsonProperties, len(s.Columns))
+	var required []string
+	for i, col := range s.Columns {
+		p := jsonProperty{}
+		p.setType(col.typ)
+		if col.HasDefault {
+			def, err := json.Marshal(jsonValue(p.baseType(), col.Default))
+			if err != nil {
+				return nil, err
+			}
+			p.Default = def
+		}
+		props[i] = jsonNamedProperty{name: col.Name, jsonProperty: p}
+
+		if col.Required {
+			required = append(required, col.Name)
+		}
+	}
+
+	return json.MarshalIndent(jsonSchema{
+		Schema:               "https://json-schema.org/draft/2020-12/schema",
+		Title:                s.Type,
+		Type:                 "object",
+		Properties:           props,
+		Required:             required,
+		AdditionalProperties: s.Extra,
+	}, "", "  ")
+}
+
+type jsonSchema struct {
+	Schema               string         `json:"$schema"`
+	Title                string         `json:"title"`
+	Type                 string         `json:"type"`
+	Properties           jsonProperties `json:"properties"`
+	Required             []string       `json:"required,omitempty"`
+	AdditionalProperties bool           `json:"additionalProperties"`
+}
+
+type jsonProperty struct {
+	// Type is a string, or a list of them for nullable types.
+	Type    any           `json:"type"`
+	Items   *jsonProperty `json:"items,omitempty"`
+	Minimum *int          `json:"minimum,omitempty"`
+	// Default is nil without a default, so that defaults such as false, 0
+	// and "" are kept.
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+// setType sets the JSON type of p from Go type t.
+func (p *jsonProperty) setType(t reflect.Type) {
+	nullable := t.Kind() == reflect.Pointer
+	if nullable {
+		t = t.Elem()
+	}
+
+	typ := "string"
+	switch {
+	case isList(t):
+		typ = "array"
+		p.Items = &jsonProperty{}
+		p.Items.setType(t.Elem())
+	case hasTypeParser(t) ||
+		reflect.PointerTo(t).Implements(textUnmarshalerType) ||
+		reflect.PointerTo(t).Implements(textMarshalerType) ||
+		t == durationType:
+	default:
+		switch t.Kind() {
+		case reflect.Bool:
+			typ = "boolean"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			typ = "integer"
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			typ = "integer"
+			p.Minimum = new(int)
+		case reflect.Float32, reflect.Float64:
+			typ = "number"
+		}
+	}
+
+	p.Type = typ
+	if nullable {
+		p.Type = []string{typ, "null"}
+	}
+}
+
+func (p jsonProperty) baseType() string {
+	if tt, ok := p.Type.([]string); ok {
+		return tt[0]
+	}
+	return p.Type.(string)
+}
+
+// jsonValue converts default value s to JSON type typ, or leaves it as a
+// string if it is not one.
+func jsonValue(typ, s string) any {
+	switch typ {
+	case "boolean":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case "integer", "number":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+		}
+	}
+	return s
+}
+
+type jsonNamedProperty struct {
+	name string
+	jsonProperty
+}
+
+// jsonProperties marshals as an object with its properties in order.
+type jsonProperties []jsonNamedProperty
+
+func (pp jsonProperties) MarshalJSON() ([]byte, error) {
+	b := &bytes.Buffer{}
+	b.WriteByte('{')
+	for i, p := range pp {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, err := json.Marshal(p.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.jsonProperty)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
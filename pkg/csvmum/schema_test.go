@@ -0,0 +1,163 @@
+package csvmum
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type schemaRecord struct {
+	ID      string            `csv:"id,required,order=1"`
+	Coords  fixtureInner      `csv:",prefix=pos_"`
+	Count   *int              `csv:"count,default=0"`
+	Ratio   float64           `csv:"ratio,omitempty"`
+	Size    uint8             `csv:"size"`
+	Active  bool              `csv:"active,default=true"`
+	Wait    time.Duration     `csv:"wait"`
+	Code    int               `csv:"code,parse=test.hex"`
+	Addrs   []netip.Addr      `csv:"addrs"`
+	Tags    []string          `csv:"tags,sep=;"`
+	Flag    yesNo             `csv:"flag"`
+	Unknown map[string]string `csv:",extra"`
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	s, err := Schema[schemaRecord]()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("columns", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		assert.Equal("csvmum.schemaRecord", s.Type)
+		assert.True(s.Extra)
+		assert.Equal([]string{"id", "pos_one", "pos_Two", "count", "ratio", "size", "active", "wait", "code", "addrs", "tags", "flag"}, s.Header())
+		assert.Equal(Column{
+			Name:       "count",
+			Field:      "Count",
+			Type:       "*int",
+			HasDefault: true,
+			Default:    "0",
+			typ:        reflect.TypeFor[*int](),
+		}, s.Columns[3])
+		assert.Equal(Column{
+			Name:      "tags",
+			Field:     "Tags",
+			Type:      "[]string",
+			Separator: ";",
+			typ:       reflect.TypeFor[[]string](),
+		}, s.Columns[10])
+		assert.Equal("pos_Two", s.Columns[2].Name)
+		assert.Equal("Coords.Two", s.Columns[2].Field)
+		assert.Equal("test.hex", s.Columns[8].Parser)
+		assert.True(s.Columns[0].Required)
+	})
+
+	t.Run("csv template", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "id,pos_one,pos_Two,count,ratio,size,active,wait,code,addrs,tags,flag\n", s.CSVTemplate())
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "| Column | Field | Type | Required | Default | Notes |\n"+
+			"|--------|-------|------|----------|---------|-------|\n"+
+			"| `id` | `ID` | `string` | Yes |  |  |\n"+
+			"| `pos_one` | `Coords.One` | `string` |  |  |  |\n"+
+			"| `pos_Two` | `Coords.Two` | `int` |  |  |  |\n"+
+			"| `count` | `Count` | `*int` |  | `0` |  |\n"+
+			"| `ratio` | `Ratio` | `float64` |  |  | Empty when zero |\n"+
+			"| `size` | `Size` | `uint8` |  |  |  |\n"+
+			"| `active` | `Active` | `bool` |  | `true` |  |\n"+
+			"| `wait` | `Wait` | `time.Duration` |  |  |  |\n"+
+			"| `code` | `Code` | `int` |  |  | Parsed with `test.hex` |\n"+
+			"| `addrs` | `Addrs` | `[]netip.Addr` |  |  | Separated by `\\|` |\n"+
+			"| `tags` | `Tags` | `[]string` |  |  | Separated by `;` |\n"+
+			"| `flag` | `Flag` | `csvmum.yesNo` |  |  |  |\n"+
+			"\nOther columns are kept as extra columns.\n", s.Markdown())
+	})
+
+	t.Run("json schema", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b, err := s.JSONSchema()
+		assert.NoError(err)
+		assert.JSONEq(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"title": "csvmum.schemaRecord",
+			"type": "object",
+			"properties": {
+				"id": {"type": "string"},
+				"pos_one": {"type": "string"},
+				"pos_Two": {"type": "integer"},
+				"count": {"type": ["integer", "null"], "default": 0},
+				"ratio": {"type": "number"},
+				"size": {"type": "integer", "minimum": 0},
+				"active": {"type": "boolean", "default": true},
+				"wait": {"type": "string"},
+				"code": {"type": "integer"},
+				"addrs": {"type": "array", "items": {"type": "string"}},
+				"tags": {"type": "array", "items": {"type": "string"}},
+				"flag": {"type": "string"}
+			},
+			"required": ["id"],
+			"additionalProperties": true
+		}`, string(b))
+	})
+
+	t.Run("zero defaults", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type defaults struct {
+			Type   int    `csv:"location_type,default=0"`
+			Active bool   `csv:"active,default=false"`
+			Name   string `csv:"name,default="`
+		}
+
+		s, err := Schema[defaults]()
+		if !assert.NoError(err) {
+			return
+		}
+
+		b, err := s.JSONSchema()
+		assert.NoError(err)
+		assert.JSONEq(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"title": "csvmum.defaults",
+			"type": "object",
+			"properties": {
+				"location_type": {"type": "integer", "default": 0},
+				"active": {"type": "boolean", "default": false},
+				"name": {"type": "string", "default": ""}
+			},
+			"additionalProperties": false
+		}`, string(b))
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Schema[int]()
+		assert.EqualError(t, err, "cannot get schema: cannot get headers: not a struct")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Schema[struct {
+			Map map[string]int `csv:"map"`
+		}]()
+		assert.EqualError(t, err, "cannot get schema: field map: unsupported type: map[string]int")
+	})
+}
@@ -1,33 +1,56 @@
 package csvmum
 
 import (
-	"encoding"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"reflect"
-	"strconv"
 )
 
 type CSVUnmarshaler[T any] struct {
-	reader    *csv.Reader
-	fieldList []int
+	reader *csv.Reader
+	binding
+	opts options
+
+	// record is decoded into before being copied out, so a failed Unmarshal
+	// leaves the caller's value untouched
+	record    *T
+	value     reflect.Value
+	generated RowUnmarshaler
+
+	// lineOffset is added to line numbers, for input that starts partway
+	// through a file
+	lineOffset int
 }
 
-func NewUnmarshaler[T any](r io.Reader) (*CSVUnmarshaler[T], error) {
+func NewUnmarshaler[T any](r io.Reader, opts ...Option) (*CSVUnmarshaler[T], error) {
+	r, _ = buildOptions(opts).stripBOMReader(r)
 	c := csv.NewReader(r)
-	return NewCSVUnmarshaler[T](c)
+	c.ReuseRecord = true
+	return NewCSVUnmarshaler[T](c, opts...)
 
 }
 
-func NewCSVUnmarshaler[T any](r *csv.Reader) (*CSVUnmarshaler[T], error) {
-	um := &CSVUnmarshaler[T]{reader: r}
+func NewCSVUnmarshaler[T any](r *csv.Reader, opts ...Option) (*CSVUnmarshaler[T], error) {
+	o := buildOptions(opts)
+	um := &CSVUnmarshaler[T]{reader: r, opts: o}
 
-	var t T
-	fm, err := buildFieldMap(reflect.TypeOf(t))
+	if err := o.configureReader(r); err != nil {
+		return um, fmt.Errorf("cannot unmarshal: %w", err)
+	}
+
+	um.record = new(T)
+	um.value = reflect.ValueOf(um.record).Elem()
+
+	c, err := codecFor(um.value.Type())
 	if err != nil {
 		return um, fmt.Errorf("cannot unmarshal: %w", err)
 	}
+	if c.decodeErr != nil {
+		return um, fmt.Errorf("cannot unmarshal: %w", c.decodeErr)
+	}
 
 	hh, err := um.reader.Read()
 	if err == io.EOF {
@@ -36,19 +59,42 @@ func NewCSVUnmarshaler[T any](r *csv.Reader) (*CSVUnmarshaler[T], error) {
 	if err != nil {
 		return um, fmt.Errorf("cannot unmarshal: %w", err)
 	}
+	o.normalizeHeaderRecord(hh)
 
-	um.fieldList = make([]int, len(hh))
-	for i, h := range hh {
-		if j, ok := fm[h]; ok {
-			um.fieldList[i] = j
-		} else {
-			um.fieldList[i] = -1
+	if um.binding, err = newBinding(c, hh, o); err != nil {
+		return um, fmt.Errorf("cannot unmarshal: %w", err)
+	}
+
+	if !o.disableGenerated {
+		if um.generated, err = generatedUnmarshaler(um.record, c); err != nil {
+			return um, fmt.Errorf("cannot unmarshal: %w", err)
 		}
 	}
 
+	if err := um.strict(o); err != nil {
+		return um, fmt.Errorf("cannot unmarshal: %w", err)
+	}
+
 	return um, nil
 }
 
+// Diagnostics returns the problems found in the header when the unmarshaler
+// was created. It is always empty with HeaderLenient.
+func (um *CSVUnmarshaler[T]) Diagnostics() []HeaderDiagnostic {
+	return um.diagnostics
+}
+
+// ExtraColumns returns the names of the columns collected in the extra field
+// of T, in the order they appear in the header. It is empty if T has no extra
+// field.
+func (um *CSVUnmarshaler[T]) ExtraColumns() []string {
+	names := make([]string, len(um.extra))
+	for k, i := range um.extra {
+		names[k] = um.header[i]
+	}
+	return names
+}
+
 func (um *CSVUnmarshaler[T]) Unmarshal(record *T) error {
 	r, err := um.reader.Read()
 	if err == io.EOF {
@@ -57,49 +103,67 @@ func (um *CSVUnmarshaler[T]) Unmarshal(record *T) error {
 	if err != nil {
 		return fmt.Errorf("cannot unmarshal: %w", err)
 	}
+	um.opts.normalizeRecord(r)
 
-	typ := reflect.TypeOf(*record)
-	n := reflect.New(typ).Elem()
+	var zero T
+	*um.record = zero
 
-	for i, j := range um.fieldList {
-		if j == -1 {
-			continue
-		}
+	if err := um.decode(um.value, um.generated, r, um.line); err != nil {
+		return err
+	}
 
-		f := n.Field(j)
+	*record = *um.record
 
-		if m, ok := f.Addr().Interface().(encoding.TextUnmarshaler); ok {
-			if err := m.UnmarshalText([]byte(r[i])); err != nil {
-				return fmt.Errorf("cannot unmarshal column %d, field %d: %w", i, j, err)
-			}
-			continue
-		}
+	return nil
+}
+
+func (um *CSVUnmarshaler[T]) line(i int) int {
+	line, _ := um.reader.FieldPos(i)
+	return line + um.lineOffset
+}
 
-		switch f.Kind() {
-		case reflect.String:
-			f.SetString(r[i])
-		case reflect.Int:
-			i, err := strconv.ParseInt(r[i], 10, 64)
-			if err != nil {
-				return fmt.Errorf("cannot unmarshal column %d, field %d: error parsing int: %w", i, j, err)
+// All returns an iterator over the remaining records. It stops after the
+// first error unless the unmarshaler was created with ContinueOnError, in
+// which case only errors from the underlying reader stop it.
+func (um *CSVUnmarshaler[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			var r T
+			err := um.Unmarshal(&r)
+			if err == io.EOF {
+				return
 			}
-			f.SetInt(i)
-		case reflect.Bool:
-			b, err := strconv.ParseBool(r[i])
-			if err != nil {
-				return fmt.Errorf("cannot unmarshal column %d, field %d: error parsing bool: %w", i, j, err)
+			if !yield(r, err) {
+				return
 			}
-			f.SetBool(b)
-		case reflect.Float64:
-			f64, err := strconv.ParseFloat(r[i], 64)
-			if err != nil {
-				return fmt.Errorf("cannot unmarshal column %d, field %d: error parsing float64: %w", i, j, err)
+			if err != nil && !(um.opts.continueOnError && isRecordError(err)) {
+				return
 			}
-			f.SetFloat(f64)
 		}
 	}
+}
 
-	reflect.ValueOf(record).Elem().Set(n)
+// ReadAll reads the remaining records. With ContinueOnError, the records that
+// could be read are returned along with the joined errors of those that could
+// not.
+func (um *CSVUnmarshaler[T]) ReadAll() ([]T, error) {
+	records := []T{}
+	var errs []error
 
-	return nil
+	for r, err := range um.All() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		records = append(records, r)
+	}
+
+	return records, errors.Join(errs...)
+}
+
+func isRecordError(err error) bool {
+	var pe *ParseError
+	var re *RecordError
+	var ce *csv.ParseError
+	return errors.As(err, &pe) || errors.As(err, &re) || errors.As(err, &ce)
 }
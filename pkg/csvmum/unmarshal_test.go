@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/csv"
 	"io"
+	"strconv"
 	"testing"
+	"time"
 	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
@@ -103,7 +105,7 @@ func TestNewCSVUnmarshaler(t *testing.T) {
 		m, err := NewCSVUnmarshaler[testType](c)
 
 		assert.NotNil(m)
-		assert.Equal([]int{0, -1, 2}, m.fieldList)
+		assert.Equal([]int{0, -1, 1}, m.fieldList)
 		assert.Nil(err)
 	})
 
@@ -127,6 +129,108 @@ func TestNewCSVUnmarshaler(t *testing.T) {
 		assert.Equal([]int{1, 0, 2}, m.fieldList)
 		assert.Nil(err)
 	})
+	t.Run("missing required column", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			First  string `csv:"first,required"`
+			Second int    `csv:"second,required"`
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("first\n")
+
+		c := csv.NewReader(b)
+		m, err := NewCSVUnmarshaler[testType](c)
+
+		assert.NotNil(m)
+		assert.EqualError(err, "cannot unmarshal: missing required column: second")
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			First bool `csv:"first,default=maybe"`
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("first\n")
+
+		c := csv.NewReader(b)
+		m, err := NewCSVUnmarshaler[testType](c)
+
+		assert.NotNil(m)
+		assert.EqualError(err, "cannot unmarshal: invalid default for first: error parsing bool: strconv.ParseBool: parsing \"maybe\": invalid syntax")
+	})
+	t.Run("header warnings", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			First  string `csv:"first"`
+			Second int    `csv:"second"`
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("First,secnod\n")
+
+		c := csv.NewReader(b)
+		m, err := NewCSVUnmarshaler[testType](c, WithHeaderPolicy(HeaderWarn))
+
+		assert.Nil(err)
+		assert.Equal([]int{-1, -1}, m.fieldList)
+		assert.Equal([]HeaderDiagnostic{
+			{Kind: MismatchedColumn, Column: 1, Header: "First", Name: "first"},
+			{Kind: UnknownColumn, Column: 2, Header: "secnod"},
+			{Kind: MissingColumn, Name: "second"},
+		}, m.Diagnostics())
+	})
+
+	t.Run("strict header", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			First  string `csv:"first"`
+			Second int    `csv:"second"`
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("first,second,third\n")
+
+		c := csv.NewReader(b)
+		m, err := NewCSVUnmarshaler[testType](c, WithHeaderPolicy(HeaderStrict))
+
+		assert.NotNil(m)
+		assert.EqualError(err, `cannot unmarshal: invalid header: unknown column 3: "third"`)
+
+		var he *HeaderError
+		assert.ErrorAs(err, &he)
+		assert.Equal([]HeaderDiagnostic{{Kind: UnknownColumn, Column: 3, Header: "third"}}, he.Diagnostics)
+	})
+
+	t.Run("lenient header", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			First  string `csv:"first"`
+			Second int    `csv:"second"`
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("First,third\n")
+
+		c := csv.NewReader(b)
+		m, err := NewCSVUnmarshaler[testType](c)
+
+		assert.Nil(err)
+		assert.Equal([]int{-1, -1}, m.fieldList)
+		assert.Empty(m.Diagnostics())
+	})
 }
 
 func TestNewUnmarshaler(t *testing.T) {
@@ -209,7 +313,7 @@ func TestUnmarshal(t *testing.T) {
 		var record testType
 		err := m.Unmarshal(&record)
 
-		assert.EqualError(err, "cannot unmarshal column 0, field 0: error parsing int: strconv.ParseInt: parsing \"one\": invalid syntax")
+		assert.EqualError(err, "cannot unmarshal line 2, column \"Int\", field Int, value \"one\": error parsing int: strconv.ParseInt: parsing \"one\": invalid syntax")
 	})
 
 	t.Run("invalid record: bool", func(t *testing.T) {
@@ -228,7 +332,7 @@ func TestUnmarshal(t *testing.T) {
 		var record testType
 		err := m.Unmarshal(&record)
 
-		assert.EqualError(err, "cannot unmarshal column 1, field 0: error parsing bool: strconv.ParseBool: parsing \"blah\": invalid syntax")
+		assert.EqualError(err, "cannot unmarshal line 2, column \"Bool\", field Bool, value \"blah\": error parsing bool: strconv.ParseBool: parsing \"blah\": invalid syntax")
 	})
 
 	t.Run("invalid record: float64", func(t *testing.T) {
@@ -248,7 +352,7 @@ func TestUnmarshal(t *testing.T) {
 		var record testType
 		err := m.Unmarshal(&record)
 
-		assert.EqualError(err, "cannot unmarshal column 0, field 1: error parsing float64: strconv.ParseFloat: parsing \"blah\": invalid syntax")
+		assert.EqualError(err, "cannot unmarshal line 2, column \"Float64\", field Float64, value \"blah\": error parsing float64: strconv.ParseFloat: parsing \"blah\": invalid syntax")
 	})
 
 	t.Run("complex", func(t *testing.T) {
@@ -310,7 +414,7 @@ func TestUnmarshal(t *testing.T) {
 		var record testType
 		err := m.Unmarshal(&record)
 
-		assert.EqualError(err, "cannot unmarshal column 0, field 0: invalid text: ~")
+		assert.EqualError(err, "cannot unmarshal line 2, column \"Field\", field Field, value \"~\": invalid text: ~")
 	})
 
 	t.Run("closed reader", func(t *testing.T) {
@@ -354,4 +458,509 @@ func TestUnmarshal(t *testing.T) {
 		assert.Nil(err)
 		assert.Equal(testType{First: "one", Third: true}, record)
 	})
+	t.Run("all kinds", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			Int      int
+			Int8     int8
+			Int16    int16
+			Int32    int32
+			Int64    int64
+			Uint     uint
+			Uint8    uint8
+			Uint16   uint16
+			Uint32   uint32
+			Uint64   uint64
+			Float32  float32
+			Float64  float64
+			Duration time.Duration
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("Int,Int8,Int16,Int32,Int64,Uint,Uint8,Uint16,Uint32,Uint64,Float32,Float64,Duration\n")
+		b.WriteString("-1,-8,-16,-32,-64,1,8,16,32,64,3.5,6.25,1h30m\n")
+
+		m, _ := NewUnmarshaler[testType](b)
+
+		var record testType
+		err := m.Unmarshal(&record)
+
+		assert.Nil(err)
+		assert.Equal(testType{
+			Int:      -1,
+			Int8:     -8,
+			Int16:    -16,
+			Int32:    -32,
+			Int64:    -64,
+			Uint:     1,
+			Uint8:    8,
+			Uint16:   16,
+			Uint32:   32,
+			Uint64:   64,
+			Float32:  3.5,
+			Float64:  6.25,
+			Duration: 90 * time.Minute,
+		}, record)
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			String *string
+			Int    *int
+			Uint8  *uint8
+			Bool   *bool
+			Float  *float64
+			Field  *customMarshalAndUnmarshal
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("String,Int,Uint8,Bool,Float,Field\n")
+		b.WriteString("one,2,3,true,4.5,~six~\n")
+		b.WriteString(",,,,,\n")
+
+		m, _ := NewUnmarshaler[testType](b)
+
+		var record testType
+		err := m.Unmarshal(&record)
+
+		assert.Nil(err)
+		assert.Equal("one", *record.String)
+		assert.Equal(2, *record.Int)
+		assert.Equal(uint8(3), *record.Uint8)
+		assert.Equal(true, *record.Bool)
+		assert.Equal(4.5, *record.Float)
+		assert.Equal(customMarshalAndUnmarshal{One: "six"}, *record.Field)
+
+		err = m.Unmarshal(&record)
+
+		assert.Nil(err)
+		assert.Equal(testType{}, record)
+	})
+
+	t.Run("invalid record: overflow", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			Int8 int8
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("Int8\n300\n")
+
+		m, _ := NewUnmarshaler[testType](b)
+
+		var record testType
+		err := m.Unmarshal(&record)
+
+		assert.EqualError(err, "cannot unmarshal line 2, column \"Int8\", field Int8, value \"300\": error parsing int8: strconv.ParseInt: parsing \"300\": value out of range")
+	})
+
+	t.Run("invalid record: uint", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			Uint uint
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("Uint\n-1\n")
+
+		m, _ := NewUnmarshaler[testType](b)
+
+		var record testType
+		err := m.Unmarshal(&record)
+
+		assert.EqualError(err, "cannot unmarshal line 2, column \"Uint\", field Uint, value \"-1\": error parsing uint: strconv.ParseUint: parsing \"-1\": invalid syntax")
+	})
+
+	t.Run("invalid record: duration", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			Duration time.Duration
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("Duration\nsoon\n")
+
+		m, _ := NewUnmarshaler[testType](b)
+
+		var record testType
+		err := m.Unmarshal(&record)
+
+		assert.EqualError(err, "cannot unmarshal line 2, column \"Duration\", field Duration, value \"soon\": error parsing duration: time: invalid duration \"soon\"")
+	})
+
+	t.Run("invalid record: pointer", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			Float *float64
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("Float\nblah\n")
+
+		m, _ := NewUnmarshaler[testType](b)
+
+		var record testType
+		err := m.Unmarshal(&record)
+
+		assert.EqualError(err, "cannot unmarshal line 2, column \"Float\", field Float, value \"blah\": error parsing float64: strconv.ParseFloat: parsing \"blah\": invalid syntax")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			Map map[string]string
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("Map\nblah\n")
+
+		_, err := NewUnmarshaler[testType](b)
+
+		assert.EqualError(err, "cannot unmarshal: field Map: unsupported type: map[string]string")
+	})
+	t.Run("required", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			First  string `csv:"first,required"`
+			Second int    `csv:"second"`
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("first,second\none,2\n,3\n")
+
+		m, _ := NewUnmarshaler[testType](b)
+
+		var record testType
+		err := m.Unmarshal(&record)
+
+		assert.Nil(err)
+		assert.Equal(testType{First: "one", Second: 2}, record)
+
+		err = m.Unmarshal(&record)
+
+		assert.EqualError(err, "cannot unmarshal line 3, column \"first\", field First, value \"\": required value is empty")
+	})
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			First  string `csv:"first,default=uno"`
+			Second int    `csv:"second,default=2"`
+			Third  *int   `csv:"third,default=3"`
+			Fourth bool   `csv:"fourth,default=true"`
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("first,second,third\none,1,1\n,,\n")
+
+		m, _ := NewUnmarshaler[testType](b)
+
+		var record testType
+		err := m.Unmarshal(&record)
+
+		one, three := 1, 3
+
+		assert.Nil(err)
+		assert.Equal(testType{First: "one", Second: 1, Third: &one, Fourth: true}, record)
+
+		err = m.Unmarshal(&record)
+
+		assert.Nil(err)
+		assert.Equal(testType{First: "uno", Second: 2, Third: &three, Fourth: true}, record)
+	})
+	t.Run("parse error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type testType struct {
+			First  string  `csv:"first"`
+			Second float64 `csv:"second"`
+			Third  int     `csv:"third,default=3"`
+		}
+
+		b := &bytes.Buffer{}
+		b.WriteString("third,first,second\n3,\"one\ntwo\",2.5\n4,three,four\n")
+
+		m, _ := NewUnmarshaler[testType](b)
+
+		var record testType
+		err := m.Unmarshal(&record)
+		assert.Nil(err)
+
+		err = m.Unmarshal(&record)
+
+		var pe *ParseError
+		assert.ErrorAs(err, &pe)
+		assert.Equal(4, pe.Line)
+		assert.Equal(3, pe.Column)
+		assert.Equal("second", pe.Header)
+		assert.Equal("Second", pe.Field)
+		assert.Equal("four", pe.Value)
+		assert.ErrorIs(err, strconv.ErrSyntax)
+	})
+}
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+
+	type testType struct {
+		First  string `csv:"first"`
+		Second int    `csv:"second"`
+	}
+
+	t.Run("simple", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("first,second\none,1\ntwo,2\nthree,3\n")
+		m, _ := NewUnmarshaler[testType](b)
+
+		records := []testType{}
+		for r, err := range m.All() {
+			assert.Nil(err)
+			records = append(records, r)
+		}
+
+		assert.Equal([]testType{{"one", 1}, {"two", 2}, {"three", 3}}, records)
+	})
+
+	t.Run("break", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("first,second\none,1\ntwo,2\nthree,3\n")
+		m, _ := NewUnmarshaler[testType](b)
+
+		for r, err := range m.All() {
+			assert.Nil(err)
+			assert.Equal(testType{"one", 1}, r)
+			break
+		}
+
+		var record testType
+		err := m.Unmarshal(&record)
+
+		assert.Nil(err)
+		assert.Equal(testType{"two", 2}, record)
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("first,second\none,1\ntwo,two\nthree,3\n")
+		m, _ := NewUnmarshaler[testType](b)
+
+		records := []testType{}
+		errs := []error{}
+		for r, err := range m.All() {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			records = append(records, r)
+		}
+
+		assert.Equal([]testType{{"one", 1}}, records)
+		assert.Len(errs, 1)
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("first,second\none,1\ntwo,two\nthree,3,extra\nfour,4\n")
+		m, _ := NewUnmarshaler[testType](b, ContinueOnError())
+
+		records := []testType{}
+		errs := []error{}
+		for r, err := range m.All() {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			records = append(records, r)
+		}
+
+		assert.Equal([]testType{{"one", 1}, {"four", 4}}, records)
+		assert.Len(errs, 2)
+
+		var pe *ParseError
+		assert.ErrorAs(errs[0], &pe)
+		assert.ErrorIs(errs[1], csv.ErrFieldCount)
+	})
+
+	t.Run("continue on error: reader error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := &closeReaderWriter{}
+		b.WriteString("first,second\n")
+		m, _ := NewUnmarshaler[testType](b, ContinueOnError())
+
+		b.Close()
+
+		n := 0
+		for _, err := range m.All() {
+			assert.EqualError(err, "cannot unmarshal: closed")
+			n++
+		}
+
+		assert.Equal(1, n)
+	})
+}
+
+func TestReadAll(t *testing.T) {
+	t.Parallel()
+
+	type testType struct {
+		First  string `csv:"first"`
+		Second int    `csv:"second"`
+	}
+
+	t.Run("simple", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("first,second\none,1\ntwo,2\n")
+		m, _ := NewUnmarshaler[testType](b)
+
+		records, err := m.ReadAll()
+
+		assert.Nil(err)
+		assert.Equal([]testType{{"one", 1}, {"two", 2}}, records)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("first,second\n")
+		m, _ := NewUnmarshaler[testType](b)
+
+		records, err := m.ReadAll()
+
+		assert.Nil(err)
+		assert.Equal([]testType{}, records)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("first,second\none,1\ntwo,two\nthree,3\n")
+		m, _ := NewUnmarshaler[testType](b)
+
+		records, err := m.ReadAll()
+
+		assert.EqualError(err, "cannot unmarshal line 3, column \"second\", field Second, value \"two\": error parsing int: strconv.ParseInt: parsing \"two\": invalid syntax")
+		assert.Equal([]testType{{"one", 1}}, records)
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("first,second\none,x\ntwo,2\nthree,y\n")
+		m, _ := NewUnmarshaler[testType](b, ContinueOnError())
+
+		records, err := m.ReadAll()
+
+		assert.Equal([]testType{{"two", 2}}, records)
+
+		errs := err.(interface{ Unwrap() []error }).Unwrap()
+		assert.Len(errs, 2)
+
+		var pe *ParseError
+		assert.ErrorAs(errs[0], &pe)
+		assert.Equal(2, pe.Line)
+		assert.ErrorAs(errs[1], &pe)
+		assert.Equal(4, pe.Line)
+	})
+}
+
+func TestUnmarshalExtra(t *testing.T) {
+	t.Parallel()
+
+	type testType struct {
+		First  string            `csv:"first"`
+		Second int               `csv:"second"`
+		Extra  map[string]string `csv:",extra"`
+	}
+
+	t.Run("collects unmapped columns", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("vendor_b,first,second,vendor_a\nb1,one,1,a1\n,two,2,\n")
+
+		um, err := NewUnmarshaler[testType](b, WithHeaderPolicy(HeaderStrict))
+		assert.NoError(err)
+		assert.Empty(um.Diagnostics())
+		assert.Equal([]string{"vendor_b", "vendor_a"}, um.ExtraColumns())
+
+		records, err := um.ReadAll()
+		assert.NoError(err)
+		assert.Equal([]testType{
+			{First: "one", Second: 1, Extra: map[string]string{"vendor_b": "b1", "vendor_a": "a1"}},
+			{First: "two", Second: 2, Extra: map[string]string{"vendor_b": "", "vendor_a": ""}},
+		}, records)
+	})
+
+	t.Run("no unmapped columns", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		um, err := NewUnmarshaler[testType](bytes.NewBufferString("first,second\none,1\n"))
+		assert.NoError(err)
+		assert.Empty(um.ExtraColumns())
+
+		var r testType
+		assert.NoError(um.Unmarshal(&r))
+		assert.Equal(testType{First: "one", Second: 1}, r)
+	})
+
+	t.Run("not a map", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type invalid struct {
+			Extra map[string]any `csv:",extra"`
+		}
+
+		_, err := NewUnmarshaler[invalid](bytes.NewBufferString("first\n"))
+		assert.EqualError(err, "cannot unmarshal: extra field Extra must be map[string]string")
+	})
+
+	t.Run("more than one", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		type invalid struct {
+			Extra map[string]string `csv:",extra"`
+			More  map[string]string `csv:",extra"`
+		}
+
+		_, err := NewUnmarshaler[invalid](bytes.NewBufferString("first\n"))
+		assert.EqualError(err, "cannot unmarshal: more than one extra field")
+	})
 }
@@ -0,0 +1,87 @@
+package csvmum
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"unicode/utf8"
+)
+
+// rowWriter is the part of csv.Writer that CSVMarshaler uses.
+type rowWriter interface {
+	Write(record []string) error
+	Flush()
+	Error() error
+}
+
+var _ rowWriter = (*csv.Writer)(nil)
+
+// validDelim reports whether r can separate cells, as csv.Reader and
+// csv.Writer require.
+func validDelim(r rune) bool {
+	return r != 0 && r != '"' && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
+}
+
+// quotingWriter writes records like csv.Writer, but quotes every cell.
+type quotingWriter struct {
+	w       *bufio.Writer
+	comma   rune
+	useCRLF bool
+	err     error
+}
+
+func newQuotingWriter(w io.Writer, comma rune, useCRLF bool) *quotingWriter {
+	return &quotingWriter{w: bufio.NewWriter(w), comma: comma, useCRLF: useCRLF}
+}
+
+func (w *quotingWriter) Write(record []string) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	for i, s := range record {
+		if i > 0 {
+			w.w.WriteRune(w.comma)
+		}
+
+		w.w.WriteByte('"')
+		for j := range len(s) {
+			switch c := s[j]; c {
+			case '"':
+				w.w.WriteString(`""`)
+			case '\r':
+				if !w.useCRLF {
+					w.w.WriteByte(c)
+				}
+			case '\n':
+				if w.useCRLF {
+					w.w.WriteString("\r\n")
+				} else {
+					w.w.WriteByte(c)
+				}
+			default:
+				w.w.WriteByte(c)
+			}
+		}
+		w.w.WriteByte('"')
+	}
+
+	// bufio.Writer errors are sticky, so the last write reports any error in
+	// the record
+	if w.useCRLF {
+		_, w.err = w.w.WriteString("\r\n")
+	} else {
+		w.err = w.w.WriteByte('\n')
+	}
+	return w.err
+}
+
+func (w *quotingWriter) Flush() {
+	if w.err == nil {
+		w.err = w.w.Flush()
+	}
+}
+
+func (w *quotingWriter) Error() error {
+	return w.err
+}
@@ -0,0 +1,75 @@
+package gtfs
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+type Attribution struct {
+	ID               string `json:"attributionId,omitempty" csv:"attribution_id"`
+	AgencyID         string `json:"agencyId,omitempty" csv:"agency_id"`
+	RouteID          string `json:"routeId,omitempty" csv:"route_id"`
+	TripID           string `json:"tripId,omitempty" csv:"trip_id"`
+	OrganizationName string `json:"organizationName" csv:"organization_name,required"`
+	IsProducer       int    `json:"isProducer,omitempty" csv:"is_producer,default=0"`
+	IsOperator       int    `json:"isOperator,omitempty" csv:"is_operator,default=0"`
+	IsAuthority      int    `json:"isAuthority,omitempty" csv:"is_authority,default=0"`
+	URL              string `json:"attributionUrl,omitempty" csv:"attribution_url"`
+	Email            string `json:"attributionEmail,omitempty" csv:"attribution_email"`
+	Phone            string `json:"attributionPhone,omitempty" csv:"attribution_phone"`
+}
+
+func (a Attribution) key() string {
+	if a.ID != "" {
+		return a.ID
+	}
+	return fmt.Sprintf("%s-%s-%s-%s", a.OrganizationName, a.AgencyID, a.RouteID, a.TripID)
+}
+
+func (a Attribution) validate() errorList {
+	var errs errorList
+
+	for _, role := range []int{a.IsProducer, a.IsOperator, a.IsAuthority} {
+		if role < AttributionRole.L || role > AttributionRole.U {
+			errs.add(fmt.Errorf("invalid attribution role: %d", role))
+		}
+	}
+	if a.IsProducer != HasRole && a.IsOperator != HasRole && a.IsAuthority != HasRole {
+		errs.add(fmt.Errorf("at least one of is producer, is operator and is authority must be 1"))
+	}
+
+	scopes := 0
+	for _, id := range []string{a.AgencyID, a.RouteID, a.TripID} {
+		if id != "" {
+			scopes++
+		}
+	}
+	if scopes > 1 {
+		errs.add(fmt.Errorf("only one of agency ID, route ID and trip ID may be given"))
+	}
+
+	return errs
+}
+
+func (a Attribution) AfterUnmarshalCSV() error {
+	return a.validate().err()
+}
+
+// validateAttributions checks that attributions refer to agencies, routes and
+// trips.
+func (s *GTFSSchedule) validateAttributions() {
+	for _, k := range slices.Sorted(maps.Keys(s.Attributions)) {
+		a := s.Attributions[k]
+
+		if _, ok := s.Agencies[a.AgencyID]; a.AgencyID != "" && !ok {
+			s.errors.add(fmt.Errorf("attributions.txt: unknown agency ID: %s", a.AgencyID))
+		}
+		if _, ok := s.Routes[a.RouteID]; a.RouteID != "" && !ok {
+			s.errors.add(fmt.Errorf("attributions.txt: unknown route ID: %s", a.RouteID))
+		}
+		if _, ok := s.Trips[a.TripID]; a.TripID != "" && !ok {
+			s.errors.add(fmt.Errorf("attributions.txt: unknown trip ID: %s", a.TripID))
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package gtfs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAttributions(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	b := bytes.NewBufferString("attribution_id,agency_id,route_id,trip_id,organization_name,is_producer,is_operator,is_authority\n" +
+		"AT1,A1,,,Transit Data Co,1,,\n" +
+		",,R1,,Metro,,1,1\n" +
+		"AT3,,,,City,,,\n" +
+		"AT4,A1,R1,,City,2,,1\n")
+
+	records := map[string]Attribution{}
+	var errs, warnings errorList
+	parse(b, records, &errs, &warnings)
+
+	assert.Equal([]string{
+		"invalid record: at least one of is producer, is operator and is authority must be 1",
+		"invalid record: invalid attribution role: 2",
+		"invalid record: only one of agency ID, route ID and trip ID may be given",
+	}, errorStrings(errs))
+	assert.Equal(map[string]Attribution{
+		"AT1":        {ID: "AT1", AgencyID: "A1", OrganizationName: "Transit Data Co", IsProducer: HasRole},
+		"Metro--R1-": {RouteID: "R1", OrganizationName: "Metro", IsOperator: HasRole, IsAuthority: HasRole},
+	}, records)
+}
+
+func TestValidateAttributions(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s := GTFSSchedule{
+		Agencies: map[string]Agency{"A1": {ID: "A1"}},
+		Attributions: map[string]Attribution{
+			"AT1": {ID: "AT1", AgencyID: "A1", OrganizationName: "Metro", IsOperator: HasRole},
+			"AT2": {ID: "AT2", AgencyID: "A9", OrganizationName: "Metro", IsOperator: HasRole},
+			"AT3": {ID: "AT3", RouteID: "R9", OrganizationName: "Metro", IsOperator: HasRole},
+			"AT4": {ID: "AT4", TripID: "T9", OrganizationName: "Metro", IsOperator: HasRole},
+		},
+	}
+
+	s.validate()
+
+	assert.Equal([]string{
+		"attributions.txt: unknown agency ID: A9",
+		"attributions.txt: unknown route ID: R9",
+		"attributions.txt: unknown trip ID: T9",
+	}, errorStrings(s.errors))
+}
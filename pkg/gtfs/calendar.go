@@ -1,16 +1,18 @@
 package gtfs
 
+import "time"
+
 type Calendar struct {
-	ServiceID string `json:"serviceId" csv:"service_id"`
-	Monday    int    `json:"monday" csv:"monday"`
-	Tuesday   int    `json:"tuesday" csv:"tuesday"`
-	Wednesday int    `json:"wednesday" csv:"wednesday"`
-	Thursday  int    `json:"thursday" csv:"thursday"`
-	Friday    int    `json:"friday" csv:"friday"`
-	Saturday  int    `json:"saturday" csv:"saturday"`
-	Sunday    int    `json:"sunday" csv:"sunday"`
-	StartDate Date   `json:"startDate" csv:"start_date"`
-	EndDate   Date   `json:"endDate" csv:"end_date"`
+	ServiceID string `json:"serviceId" csv:"service_id,required"`
+	Monday    int    `json:"monday" csv:"monday,required"`
+	Tuesday   int    `json:"tuesday" csv:"tuesday,required"`
+	Wednesday int    `json:"wednesday" csv:"wednesday,required"`
+	Thursday  int    `json:"thursday" csv:"thursday,required"`
+	Friday    int    `json:"friday" csv:"friday,required"`
+	Saturday  int    `json:"saturday" csv:"saturday,required"`
+	Sunday    int    `json:"sunday" csv:"sunday,required"`
+	StartDate Date   `json:"startDate" csv:"start_date,required"`
+	EndDate   Date   `json:"endDate" csv:"end_date,required"`
 }
 
 func (c Calendar) key() string {
@@ -21,3 +23,12 @@ func (c Calendar) validate() errorList {
 	var errs errorList
 	return errs
 }
+
+func (c Calendar) AfterUnmarshalCSV() error {
+	return c.validate().err()
+}
+
+// runsOn reports whether c has service on weekday wd.
+func (c Calendar) runsOn(wd time.Weekday) bool {
+	return [...]int{c.Sunday, c.Monday, c.Tuesday, c.Wednesday, c.Thursday, c.Friday, c.Saturday}[wd] == 1
+}
@@ -3,27 +3,27 @@ package gtfs
 import "fmt"
 
 type CalendarDate struct {
-	ServiceID     string `json:"serviceId" csv:"service_id"`
-	Date          Date   `json:"date" csv:"date"`
-	ExceptionType int    `json:"exceptionType" csv:"exception_type"`
+	ServiceID     string `json:"serviceId" csv:"service_id,required"`
+	Date          Date   `json:"date" csv:"date,required"`
+	ExceptionType int    `json:"exceptionType" csv:"exception_type,required"`
 }
 
+// key is the service ID and date, since a service has a row for each date
+// it is added or removed on.
 func (c CalendarDate) key() string {
-	return c.ServiceID
+	return fmt.Sprintf("%s-%s", c.ServiceID, c.Date.Format(dateFormat))
 }
 
 func (c CalendarDate) validate() errorList {
 	var errs errorList
 
-	if c.ServiceID == "" {
-		errs.add(fmt.Errorf("service ID is required"))
-	}
-	if c.Date.IsZero() {
-		errs.add(fmt.Errorf("date is required"))
-	}
 	if c.ExceptionType != 1 && c.ExceptionType != 2 {
 		errs.add(fmt.Errorf("invalid exception type: %d", c.ExceptionType))
 	}
 
 	return errs
 }
+
+func (c CalendarDate) AfterUnmarshalCSV() error {
+	return c.validate().err()
+}
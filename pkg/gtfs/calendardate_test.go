@@ -0,0 +1,31 @@
+package gtfs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCalendarDates(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	// a service has a row for each of its exception dates
+	b := bytes.NewBufferString("service_id,date,exception_type\n" +
+		"WK,20240101,2\n" +
+		"WK,20240704,2\n" +
+		"WE,20240101,1\n" +
+		"WK,20240704,1\n")
+
+	records := map[string]CalendarDate{}
+	var errs, warnings errorList
+	parse(b, records, &errs, &warnings)
+
+	assert.Equal([]string{"duplicate key: WK-20240704"}, errorStrings(errs))
+	assert.Equal(map[string]CalendarDate{
+		"WK-20240101": {ServiceID: "WK", Date: date("20240101"), ExceptionType: Removed},
+		"WK-20240704": {ServiceID: "WK", Date: date("20240704"), ExceptionType: Removed},
+		"WE-20240101": {ServiceID: "WE", Date: date("20240101"), ExceptionType: Added},
+	}, records)
+}
@@ -19,6 +19,20 @@ func Overview(c map[string]GTFSSchedule) string {
 		o += fmt.Sprintf("  %d trips\n", len(s.Trips))
 		o += fmt.Sprintf("  %d stop times\n", len(s.StopTimes))
 		o += fmt.Sprintf("  %d levels\n", len(s.Levels))
+		o += fmt.Sprintf("  %d shapes\n", len(s.Shapes))
+		o += fmt.Sprintf("  %d frequencies\n", len(s.Frequencies))
+		o += fmt.Sprintf("  %d transfers\n", len(s.Transfers))
+		o += fmt.Sprintf("  %d pathways\n", len(s.Pathways))
+		o += fmt.Sprintf("  %d attributions\n", len(s.Attributions))
+		o += fmt.Sprintf("  %d fares\n", len(s.FareAttributes))
+		if s.FeedInfo != nil && s.FeedInfo.Version != "" {
+			o += fmt.Sprintf("  feed version %s\n", s.FeedInfo.Version)
+		}
+		if w, ok := s.ServiceWindow(); ok {
+			start, _ := w.Start.MarshalText()
+			end, _ := w.End.MarshalText()
+			o += fmt.Sprintf("  service from %s to %s\n", start, end)
+		}
 		o += fmt.Sprintf("  %d errors\n", len(s.errors))
 		o += "\n"
 	}
@@ -26,11 +40,11 @@ func Overview(c map[string]GTFSSchedule) string {
 	return o
 }
 
-func CreateGTFSCollection(zipFiles []string) (map[string]GTFSSchedule, error) {
+func CreateGTFSCollection(zipFiles []string, opts ...ScheduleOption) (map[string]GTFSSchedule, error) {
 	sc := make(map[string]GTFSSchedule)
 
 	for _, path := range zipFiles {
-		s, err := OpenScheduleFromZipFile(path)
+		s, err := OpenScheduleFromZipFile(path, opts...)
 		if err != nil {
 			return sc, err
 		}
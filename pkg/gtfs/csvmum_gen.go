@@ -0,0 +1,1697 @@
+// Code generated by csvmumgen. DO NOT EDIT.
+
+package gtfs
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/bridgelightcloud/bogie/pkg/csvmum"
+)
+
+func (r *Agency) CSVColumns() []string {
+	return []string{"agency_id", "agency_name", "agency_url", "agency_timezone", "agency_lang", "agency_phone", "agency_fare_url", "agency_email"}
+}
+
+func (r *Agency) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		r.ID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 1, Err: csvmum.ErrRequired}
+		}
+		r.Name = s
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 2, Err: csvmum.ErrRequired}
+		}
+		r.URL = s
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 3, Err: csvmum.ErrRequired}
+		}
+		r.Timezone = s
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		r.Lang = s
+	}
+
+	if i := columns[5]; i != -1 {
+		s := record[i]
+		r.Phone = s
+	}
+
+	if i := columns[6]; i != -1 {
+		s := record[i]
+		r.FareURL = s
+	}
+
+	if i := columns[7]; i != -1 {
+		s := record[i]
+		r.AgencyEmail = s
+	}
+
+	return nil
+}
+
+func (r *Agency) MarshalCSVRow(row []string) error {
+	row[0] = r.ID
+	row[1] = r.Name
+	row[2] = r.URL
+	row[3] = r.Timezone
+	row[4] = r.Lang
+	row[5] = r.Phone
+	row[6] = r.FareURL
+	row[7] = r.AgencyEmail
+	return nil
+}
+
+func (r *Attribution) CSVColumns() []string {
+	return []string{"attribution_id", "agency_id", "route_id", "trip_id", "organization_name", "is_producer", "is_operator", "is_authority", "attribution_url", "attribution_email", "attribution_phone"}
+}
+
+func (r *Attribution) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		r.ID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		r.AgencyID = s
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		r.RouteID = s
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		r.TripID = s
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 4, Err: csvmum.ErrRequired}
+		}
+		r.OrganizationName = s
+	}
+
+	{
+		var s string
+		if i := columns[5]; i != -1 {
+			s = record[i]
+		}
+		if s == "" {
+			s = "0"
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 5, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.IsProducer = int(v)
+	}
+
+	{
+		var s string
+		if i := columns[6]; i != -1 {
+			s = record[i]
+		}
+		if s == "" {
+			s = "0"
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 6, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.IsOperator = int(v)
+	}
+
+	{
+		var s string
+		if i := columns[7]; i != -1 {
+			s = record[i]
+		}
+		if s == "" {
+			s = "0"
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 7, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.IsAuthority = int(v)
+	}
+
+	if i := columns[8]; i != -1 {
+		s := record[i]
+		r.URL = s
+	}
+
+	if i := columns[9]; i != -1 {
+		s := record[i]
+		r.Email = s
+	}
+
+	if i := columns[10]; i != -1 {
+		s := record[i]
+		r.Phone = s
+	}
+
+	return nil
+}
+
+func (r *Attribution) MarshalCSVRow(row []string) error {
+	row[0] = r.ID
+	row[1] = r.AgencyID
+	row[2] = r.RouteID
+	row[3] = r.TripID
+	row[4] = r.OrganizationName
+	row[5] = strconv.FormatInt(int64(r.IsProducer), 10)
+	row[6] = strconv.FormatInt(int64(r.IsOperator), 10)
+	row[7] = strconv.FormatInt(int64(r.IsAuthority), 10)
+	row[8] = r.URL
+	row[9] = r.Email
+	row[10] = r.Phone
+	return nil
+}
+
+func (r *Calendar) CSVColumns() []string {
+	return []string{"service_id", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday", "start_date", "end_date"}
+}
+
+func (r *Calendar) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.ServiceID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 1, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 1, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.Monday = int(v)
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 2, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 2, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.Tuesday = int(v)
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 3, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 3, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.Wednesday = int(v)
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 4, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 4, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.Thursday = int(v)
+	}
+
+	if i := columns[5]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 5, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 5, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.Friday = int(v)
+	}
+
+	if i := columns[6]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 6, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 6, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.Saturday = int(v)
+	}
+
+	if i := columns[7]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 7, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 7, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.Sunday = int(v)
+	}
+
+	if i := columns[8]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 8, Err: csvmum.ErrRequired}
+		}
+		if err := r.StartDate.UnmarshalText([]byte(s)); err != nil {
+			return &csvmum.FieldError{Index: 8, Err: err}
+		}
+	}
+
+	if i := columns[9]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 9, Err: csvmum.ErrRequired}
+		}
+		if err := r.EndDate.UnmarshalText([]byte(s)); err != nil {
+			return &csvmum.FieldError{Index: 9, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func (r *Calendar) MarshalCSVRow(row []string) error {
+	row[0] = r.ServiceID
+	row[1] = strconv.FormatInt(int64(r.Monday), 10)
+	row[2] = strconv.FormatInt(int64(r.Tuesday), 10)
+	row[3] = strconv.FormatInt(int64(r.Wednesday), 10)
+	row[4] = strconv.FormatInt(int64(r.Thursday), 10)
+	row[5] = strconv.FormatInt(int64(r.Friday), 10)
+	row[6] = strconv.FormatInt(int64(r.Saturday), 10)
+	row[7] = strconv.FormatInt(int64(r.Sunday), 10)
+	if b, err := r.StartDate.MarshalText(); err != nil {
+		return err
+	} else {
+		row[8] = string(b)
+	}
+	if b, err := r.EndDate.MarshalText(); err != nil {
+		return err
+	} else {
+		row[9] = string(b)
+	}
+	return nil
+}
+
+func (r *CalendarDate) CSVColumns() []string {
+	return []string{"service_id", "date", "exception_type"}
+}
+
+func (r *CalendarDate) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.ServiceID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 1, Err: csvmum.ErrRequired}
+		}
+		if err := r.Date.UnmarshalText([]byte(s)); err != nil {
+			return &csvmum.FieldError{Index: 1, Err: err}
+		}
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 2, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 2, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.ExceptionType = int(v)
+	}
+
+	return nil
+}
+
+func (r *CalendarDate) MarshalCSVRow(row []string) error {
+	row[0] = r.ServiceID
+	if b, err := r.Date.MarshalText(); err != nil {
+		return err
+	} else {
+		row[1] = string(b)
+	}
+	row[2] = strconv.FormatInt(int64(r.ExceptionType), 10)
+	return nil
+}
+
+func (r *Coords) CSVColumns() []string {
+	return []string{"lat", "lon"}
+}
+
+func (r *Coords) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p float64
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return &csvmum.FieldError{Index: 0, Err: fmt.Errorf("error parsing float64: %w", err)}
+			}
+			p = v
+			r.Latitude = &p
+		}
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p float64
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return &csvmum.FieldError{Index: 1, Err: fmt.Errorf("error parsing float64: %w", err)}
+			}
+			p = v
+			r.Longitude = &p
+		}
+	}
+
+	return nil
+}
+
+func (r *Coords) MarshalCSVRow(row []string) error {
+	if r.Latitude == nil {
+		row[0] = ""
+	} else {
+		row[0] = strconv.FormatFloat(*r.Latitude, 'f', -1, 64)
+	}
+	if r.Longitude == nil {
+		row[1] = ""
+	} else {
+		row[1] = strconv.FormatFloat(*r.Longitude, 'f', -1, 64)
+	}
+	return nil
+}
+
+func (r *FareAttribute) CSVColumns() []string {
+	return []string{"fare_id", "price", "currency_type", "payment_method", "transfers", "agency_id", "transfer_duration"}
+}
+
+func (r *FareAttribute) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.FareID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 1, Err: csvmum.ErrRequired}
+		}
+		if err := r.Price.UnmarshalText([]byte(s)); err != nil {
+			return &csvmum.FieldError{Index: 1, Err: err}
+		}
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 2, Err: csvmum.ErrRequired}
+		}
+		if s != "" {
+			if err := csvmum.Parse("currency", s, &r.CurrencyType); err != nil {
+				return &csvmum.FieldError{Index: 2, Err: err}
+			}
+		}
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 3, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 3, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.PaymentMethod = int(v)
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p int
+			v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+			if err != nil {
+				return &csvmum.FieldError{Index: 4, Err: fmt.Errorf("error parsing int: %w", err)}
+			}
+			p = int(v)
+			r.Transfers = &p
+		}
+	}
+
+	if i := columns[5]; i != -1 {
+		s := record[i]
+		r.AgencyID = s
+	}
+
+	if i := columns[6]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p int
+			v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+			if err != nil {
+				return &csvmum.FieldError{Index: 6, Err: fmt.Errorf("error parsing int: %w", err)}
+			}
+			p = int(v)
+			r.TransferDuration = &p
+		}
+	}
+
+	return nil
+}
+
+func (r *FareAttribute) MarshalCSVRow(row []string) error {
+	row[0] = r.FareID
+	if b, err := r.Price.MarshalText(); err != nil {
+		return err
+	} else {
+		row[1] = string(b)
+	}
+	if v, err := csvmum.Format("currency", r.CurrencyType); err != nil {
+		return err
+	} else {
+		row[2] = v
+	}
+	row[3] = strconv.FormatInt(int64(r.PaymentMethod), 10)
+	if r.Transfers == nil {
+		row[4] = ""
+	} else {
+		row[4] = strconv.FormatInt(int64(*r.Transfers), 10)
+	}
+	row[5] = r.AgencyID
+	if r.TransferDuration == nil {
+		row[6] = ""
+	} else {
+		row[6] = strconv.FormatInt(int64(*r.TransferDuration), 10)
+	}
+	return nil
+}
+
+func (r *FareRule) CSVColumns() []string {
+	return []string{"fare_id", "route_id", "origin_id", "destination_id", "contains_id"}
+}
+
+func (r *FareRule) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.FareID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		r.RouteID = s
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		r.OriginID = s
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		r.DestinationID = s
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		r.ContainsID = s
+	}
+
+	return nil
+}
+
+func (r *FareRule) MarshalCSVRow(row []string) error {
+	row[0] = r.FareID
+	row[1] = r.RouteID
+	row[2] = r.OriginID
+	row[3] = r.DestinationID
+	row[4] = r.ContainsID
+	return nil
+}
+
+func (r *FeedInfo) CSVColumns() []string {
+	return []string{"feed_publisher_name", "feed_publisher_url", "feed_lang", "default_lang", "feed_start_date", "feed_end_date", "feed_version", "feed_contact_email", "feed_contact_url"}
+}
+
+func (r *FeedInfo) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.PublisherName = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 1, Err: csvmum.ErrRequired}
+		}
+		r.PublisherURL = s
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 2, Err: csvmum.ErrRequired}
+		}
+		r.Lang = s
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		r.DefaultLang = s
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p Date
+			if err := p.UnmarshalText([]byte(s)); err != nil {
+				return &csvmum.FieldError{Index: 4, Err: err}
+			}
+			r.StartDate = &p
+		}
+	}
+
+	if i := columns[5]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p Date
+			if err := p.UnmarshalText([]byte(s)); err != nil {
+				return &csvmum.FieldError{Index: 5, Err: err}
+			}
+			r.EndDate = &p
+		}
+	}
+
+	if i := columns[6]; i != -1 {
+		s := record[i]
+		r.Version = s
+	}
+
+	if i := columns[7]; i != -1 {
+		s := record[i]
+		r.ContactEmail = s
+	}
+
+	if i := columns[8]; i != -1 {
+		s := record[i]
+		r.ContactURL = s
+	}
+
+	return nil
+}
+
+func (r *FeedInfo) MarshalCSVRow(row []string) error {
+	row[0] = r.PublisherName
+	row[1] = r.PublisherURL
+	row[2] = r.Lang
+	row[3] = r.DefaultLang
+	if r.StartDate == nil {
+		row[4] = ""
+	} else {
+		if b, err := r.StartDate.MarshalText(); err != nil {
+			return err
+		} else {
+			row[4] = string(b)
+		}
+	}
+	if r.EndDate == nil {
+		row[5] = ""
+	} else {
+		if b, err := r.EndDate.MarshalText(); err != nil {
+			return err
+		} else {
+			row[5] = string(b)
+		}
+	}
+	row[6] = r.Version
+	row[7] = r.ContactEmail
+	row[8] = r.ContactURL
+	return nil
+}
+
+func (r *Frequency) CSVColumns() []string {
+	return []string{"trip_id", "start_time", "end_time", "headway_secs", "exact_times"}
+}
+
+func (r *Frequency) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.TripID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 1, Err: csvmum.ErrRequired}
+		}
+		if err := r.StartTime.UnmarshalText([]byte(s)); err != nil {
+			return &csvmum.FieldError{Index: 1, Err: err}
+		}
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 2, Err: csvmum.ErrRequired}
+		}
+		if err := r.EndTime.UnmarshalText([]byte(s)); err != nil {
+			return &csvmum.FieldError{Index: 2, Err: err}
+		}
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 3, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 3, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.HeadwaySecs = int(v)
+	}
+
+	{
+		var s string
+		if i := columns[4]; i != -1 {
+			s = record[i]
+		}
+		if s == "" {
+			s = "0"
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 4, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.ExactTimes = int(v)
+	}
+
+	return nil
+}
+
+func (r *Frequency) MarshalCSVRow(row []string) error {
+	row[0] = r.TripID
+	if b, err := r.StartTime.MarshalText(); err != nil {
+		return err
+	} else {
+		row[1] = string(b)
+	}
+	if b, err := r.EndTime.MarshalText(); err != nil {
+		return err
+	} else {
+		row[2] = string(b)
+	}
+	row[3] = strconv.FormatInt(int64(r.HeadwaySecs), 10)
+	row[4] = strconv.FormatInt(int64(r.ExactTimes), 10)
+	return nil
+}
+
+func (r *Level) CSVColumns() []string {
+	return []string{"level_id", "level_index", "level_name"}
+}
+
+func (r *Level) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.ID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 1, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return &csvmum.FieldError{Index: 1, Err: fmt.Errorf("error parsing float64: %w", err)}
+		}
+		r.Index = v
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		r.Name = s
+	}
+
+	return nil
+}
+
+func (r *Level) MarshalCSVRow(row []string) error {
+	row[0] = r.ID
+	row[1] = strconv.FormatFloat(r.Index, 'f', -1, 64)
+	row[2] = r.Name
+	return nil
+}
+
+func (r *Pathway) CSVColumns() []string {
+	return []string{"pathway_id", "from_stop_id", "to_stop_id", "pathway_mode", "is_bidirectional", "length", "traversal_time", "stair_count", "max_slope", "min_width", "signposted_as", "reversed_signposted_as"}
+}
+
+func (r *Pathway) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.ID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 1, Err: csvmum.ErrRequired}
+		}
+		r.FromStopID = s
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 2, Err: csvmum.ErrRequired}
+		}
+		r.ToStopID = s
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 3, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 3, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.Mode = int(v)
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 4, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 4, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.IsBidirectional = int(v)
+	}
+
+	if i := columns[5]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p float64
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return &csvmum.FieldError{Index: 5, Err: fmt.Errorf("error parsing float64: %w", err)}
+			}
+			p = v
+			r.Length = &p
+		}
+	}
+
+	if i := columns[6]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p int
+			v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+			if err != nil {
+				return &csvmum.FieldError{Index: 6, Err: fmt.Errorf("error parsing int: %w", err)}
+			}
+			p = int(v)
+			r.TraversalTime = &p
+		}
+	}
+
+	if i := columns[7]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p int
+			v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+			if err != nil {
+				return &csvmum.FieldError{Index: 7, Err: fmt.Errorf("error parsing int: %w", err)}
+			}
+			p = int(v)
+			r.StairCount = &p
+		}
+	}
+
+	if i := columns[8]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p float64
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return &csvmum.FieldError{Index: 8, Err: fmt.Errorf("error parsing float64: %w", err)}
+			}
+			p = v
+			r.MaxSlope = &p
+		}
+	}
+
+	if i := columns[9]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p float64
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return &csvmum.FieldError{Index: 9, Err: fmt.Errorf("error parsing float64: %w", err)}
+			}
+			p = v
+			r.MinWidth = &p
+		}
+	}
+
+	if i := columns[10]; i != -1 {
+		s := record[i]
+		r.SignpostedAs = s
+	}
+
+	if i := columns[11]; i != -1 {
+		s := record[i]
+		r.ReversedSignpostedAs = s
+	}
+
+	return nil
+}
+
+func (r *Pathway) MarshalCSVRow(row []string) error {
+	row[0] = r.ID
+	row[1] = r.FromStopID
+	row[2] = r.ToStopID
+	row[3] = strconv.FormatInt(int64(r.Mode), 10)
+	row[4] = strconv.FormatInt(int64(r.IsBidirectional), 10)
+	if r.Length == nil {
+		row[5] = ""
+	} else {
+		row[5] = strconv.FormatFloat(*r.Length, 'f', -1, 64)
+	}
+	if r.TraversalTime == nil {
+		row[6] = ""
+	} else {
+		row[6] = strconv.FormatInt(int64(*r.TraversalTime), 10)
+	}
+	if r.StairCount == nil {
+		row[7] = ""
+	} else {
+		row[7] = strconv.FormatInt(int64(*r.StairCount), 10)
+	}
+	if r.MaxSlope == nil {
+		row[8] = ""
+	} else {
+		row[8] = strconv.FormatFloat(*r.MaxSlope, 'f', -1, 64)
+	}
+	if r.MinWidth == nil {
+		row[9] = ""
+	} else {
+		row[9] = strconv.FormatFloat(*r.MinWidth, 'f', -1, 64)
+	}
+	row[10] = r.SignpostedAs
+	row[11] = r.ReversedSignpostedAs
+	return nil
+}
+
+func (r *Route) CSVColumns() []string {
+	return []string{"route_id", "agency_id", "route_short_name", "route_long_name", "route_desc", "route_type", "route_url", "route_color", "route_text_color", "route_sort_order", "continuous_pickup", "continuous_drop_off", "network_id"}
+}
+
+func (r *Route) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.ID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		r.AgencyID = s
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		r.ShortName = s
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		r.LongName = s
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		r.Desc = s
+	}
+
+	if i := columns[5]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 5, Err: csvmum.ErrRequired}
+		}
+		r.Type = s
+	}
+
+	if i := columns[6]; i != -1 {
+		s := record[i]
+		r.URL = s
+	}
+
+	if i := columns[7]; i != -1 {
+		s := record[i]
+		if s != "" {
+			if err := csvmum.Parse("color", s, &r.Color); err != nil {
+				return &csvmum.FieldError{Index: 7, Err: err}
+			}
+		}
+	}
+
+	if i := columns[8]; i != -1 {
+		s := record[i]
+		if s != "" {
+			if err := csvmum.Parse("color", s, &r.TextColor); err != nil {
+				return &csvmum.FieldError{Index: 8, Err: err}
+			}
+		}
+	}
+
+	if i := columns[9]; i != -1 {
+		s := record[i]
+		r.SortOrder = s
+	}
+
+	if i := columns[10]; i != -1 {
+		s := record[i]
+		r.ContinuousPickup = s
+	}
+
+	if i := columns[11]; i != -1 {
+		s := record[i]
+		r.ContinuousDropOff = s
+	}
+
+	if i := columns[12]; i != -1 {
+		s := record[i]
+		r.NetworkID = s
+	}
+
+	return nil
+}
+
+func (r *Route) MarshalCSVRow(row []string) error {
+	row[0] = r.ID
+	row[1] = r.AgencyID
+	row[2] = r.ShortName
+	row[3] = r.LongName
+	row[4] = r.Desc
+	row[5] = r.Type
+	row[6] = r.URL
+	if v, err := csvmum.Format("color", r.Color); err != nil {
+		return err
+	} else {
+		row[7] = v
+	}
+	if v, err := csvmum.Format("color", r.TextColor); err != nil {
+		return err
+	} else {
+		row[8] = v
+	}
+	row[9] = r.SortOrder
+	row[10] = r.ContinuousPickup
+	row[11] = r.ContinuousDropOff
+	row[12] = r.NetworkID
+	return nil
+}
+
+func (r *ShapePoint) CSVColumns() []string {
+	return []string{"shape_id", "shape_pt_lat", "shape_pt_lon", "shape_pt_sequence", "shape_dist_traveled"}
+}
+
+func (r *ShapePoint) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.ShapeID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p float64
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return &csvmum.FieldError{Index: 1, Err: fmt.Errorf("error parsing float64: %w", err)}
+			}
+			p = v
+			r.Coords.Latitude = &p
+		}
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p float64
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return &csvmum.FieldError{Index: 2, Err: fmt.Errorf("error parsing float64: %w", err)}
+			}
+			p = v
+			r.Coords.Longitude = &p
+		}
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 3, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 3, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.Sequence = int(v)
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p float64
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return &csvmum.FieldError{Index: 4, Err: fmt.Errorf("error parsing float64: %w", err)}
+			}
+			p = v
+			r.DistTraveled = &p
+		}
+	}
+
+	return nil
+}
+
+func (r *ShapePoint) MarshalCSVRow(row []string) error {
+	row[0] = r.ShapeID
+	if r.Coords.Latitude == nil {
+		row[1] = ""
+	} else {
+		row[1] = strconv.FormatFloat(*r.Coords.Latitude, 'f', -1, 64)
+	}
+	if r.Coords.Longitude == nil {
+		row[2] = ""
+	} else {
+		row[2] = strconv.FormatFloat(*r.Coords.Longitude, 'f', -1, 64)
+	}
+	row[3] = strconv.FormatInt(int64(r.Sequence), 10)
+	if r.DistTraveled == nil {
+		row[4] = ""
+	} else {
+		row[4] = strconv.FormatFloat(*r.DistTraveled, 'f', -1, 64)
+	}
+	return nil
+}
+
+func (r *Stop) CSVColumns() []string {
+	return []string{"stop_id", "stop_code", "stop_name", "tts_stop_name", "stop_desc", "stop_lat", "stop_lon", "zone_id", "stop_url", "location_type", "parent_station", "stop_timezone", "wheelchair_boarding", "level_id", "platform_code"}
+}
+
+func (r *Stop) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.ID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		r.Code = s
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		r.Name = s
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		r.TTSName = s
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		r.Desc = s
+	}
+
+	if i := columns[5]; i != -1 {
+		s := record[i]
+		r.Latitude = s
+	}
+
+	if i := columns[6]; i != -1 {
+		s := record[i]
+		r.Longitude = s
+	}
+
+	if i := columns[7]; i != -1 {
+		s := record[i]
+		r.ZoneID = s
+	}
+
+	if i := columns[8]; i != -1 {
+		s := record[i]
+		r.URL = s
+	}
+
+	{
+		var s string
+		if i := columns[9]; i != -1 {
+			s = record[i]
+		}
+		if s == "" {
+			s = "0"
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 9, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.LocationType = int(v)
+	}
+
+	if i := columns[10]; i != -1 {
+		s := record[i]
+		r.ParentStation = s
+	}
+
+	if i := columns[11]; i != -1 {
+		s := record[i]
+		r.Timezone = s
+	}
+
+	if i := columns[12]; i != -1 {
+		s := record[i]
+		r.WheelchairBoarding = s
+	}
+
+	if i := columns[13]; i != -1 {
+		s := record[i]
+		r.LevelID = s
+	}
+
+	if i := columns[14]; i != -1 {
+		s := record[i]
+		r.PlatformCode = s
+	}
+
+	return nil
+}
+
+func (r *Stop) MarshalCSVRow(row []string) error {
+	row[0] = r.ID
+	row[1] = r.Code
+	row[2] = r.Name
+	row[3] = r.TTSName
+	row[4] = r.Desc
+	row[5] = r.Latitude
+	row[6] = r.Longitude
+	row[7] = r.ZoneID
+	row[8] = r.URL
+	row[9] = strconv.FormatInt(int64(r.LocationType), 10)
+	row[10] = r.ParentStation
+	row[11] = r.Timezone
+	row[12] = r.WheelchairBoarding
+	row[13] = r.LevelID
+	row[14] = r.PlatformCode
+	return nil
+}
+
+func (r *StopTime) CSVColumns() []string {
+	return []string{"trip_id", "arrival_time", "departure_time", "stop_id", "location_group_id", "location_id", "stop_sequence", "stop_headsign", "start_pickup_drop_off_window", "end_pickup_drop_off_window", "pickup_type", "drop_off_type", "continuous_pickup", "continuous_drop_off", "shape_dist_traveled", "timepoint", "pickup_booking_rule_id", "drop_off_booking_rule_id"}
+}
+
+func (r *StopTime) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.TripID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if err := r.ArrivalTime.UnmarshalText([]byte(s)); err != nil {
+			return &csvmum.FieldError{Index: 1, Err: err}
+		}
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		if err := r.DepartureTime.UnmarshalText([]byte(s)); err != nil {
+			return &csvmum.FieldError{Index: 2, Err: err}
+		}
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		r.StopID = s
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		r.LocationGroupID = s
+	}
+
+	if i := columns[5]; i != -1 {
+		s := record[i]
+		r.LocationID = s
+	}
+
+	if i := columns[6]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 6, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 6, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.StopSequence = int(v)
+	}
+
+	if i := columns[7]; i != -1 {
+		s := record[i]
+		r.StopHeadsign = s
+	}
+
+	if i := columns[8]; i != -1 {
+		s := record[i]
+		if err := r.StartPickupDropOffWindow.UnmarshalText([]byte(s)); err != nil {
+			return &csvmum.FieldError{Index: 8, Err: err}
+		}
+	}
+
+	if i := columns[9]; i != -1 {
+		s := record[i]
+		if err := r.EndPickupDropOffWindow.UnmarshalText([]byte(s)); err != nil {
+			return &csvmum.FieldError{Index: 9, Err: err}
+		}
+	}
+
+	if i := columns[10]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p int
+			v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+			if err != nil {
+				return &csvmum.FieldError{Index: 10, Err: fmt.Errorf("error parsing int: %w", err)}
+			}
+			p = int(v)
+			r.PickupType = &p
+		}
+	}
+
+	if i := columns[11]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p int
+			v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+			if err != nil {
+				return &csvmum.FieldError{Index: 11, Err: fmt.Errorf("error parsing int: %w", err)}
+			}
+			p = int(v)
+			r.DropOffType = &p
+		}
+	}
+
+	if i := columns[12]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p int
+			v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+			if err != nil {
+				return &csvmum.FieldError{Index: 12, Err: fmt.Errorf("error parsing int: %w", err)}
+			}
+			p = int(v)
+			r.ContinuousPickup = &p
+		}
+	}
+
+	if i := columns[13]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p int
+			v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+			if err != nil {
+				return &csvmum.FieldError{Index: 13, Err: fmt.Errorf("error parsing int: %w", err)}
+			}
+			p = int(v)
+			r.ContinuousDropOff = &p
+		}
+	}
+
+	if i := columns[14]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p float64
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return &csvmum.FieldError{Index: 14, Err: fmt.Errorf("error parsing float64: %w", err)}
+			}
+			p = v
+			r.ShapeDistTraveled = &p
+		}
+	}
+
+	if i := columns[15]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p int
+			v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+			if err != nil {
+				return &csvmum.FieldError{Index: 15, Err: fmt.Errorf("error parsing int: %w", err)}
+			}
+			p = int(v)
+			r.Timepoint = &p
+		}
+	}
+
+	if i := columns[16]; i != -1 {
+		s := record[i]
+		r.PickupBookingRuleId = s
+	}
+
+	if i := columns[17]; i != -1 {
+		s := record[i]
+		r.DropOffBookingRuleId = s
+	}
+
+	return nil
+}
+
+func (r *StopTime) MarshalCSVRow(row []string) error {
+	row[0] = r.TripID
+	if b, err := r.ArrivalTime.MarshalText(); err != nil {
+		return err
+	} else {
+		row[1] = string(b)
+	}
+	if b, err := r.DepartureTime.MarshalText(); err != nil {
+		return err
+	} else {
+		row[2] = string(b)
+	}
+	row[3] = r.StopID
+	row[4] = r.LocationGroupID
+	row[5] = r.LocationID
+	row[6] = strconv.FormatInt(int64(r.StopSequence), 10)
+	row[7] = r.StopHeadsign
+	if b, err := r.StartPickupDropOffWindow.MarshalText(); err != nil {
+		return err
+	} else {
+		row[8] = string(b)
+	}
+	if b, err := r.EndPickupDropOffWindow.MarshalText(); err != nil {
+		return err
+	} else {
+		row[9] = string(b)
+	}
+	if r.PickupType == nil {
+		row[10] = ""
+	} else {
+		row[10] = strconv.FormatInt(int64(*r.PickupType), 10)
+	}
+	if r.DropOffType == nil {
+		row[11] = ""
+	} else {
+		row[11] = strconv.FormatInt(int64(*r.DropOffType), 10)
+	}
+	if r.ContinuousPickup == nil {
+		row[12] = ""
+	} else {
+		row[12] = strconv.FormatInt(int64(*r.ContinuousPickup), 10)
+	}
+	if r.ContinuousDropOff == nil {
+		row[13] = ""
+	} else {
+		row[13] = strconv.FormatInt(int64(*r.ContinuousDropOff), 10)
+	}
+	if r.ShapeDistTraveled == nil {
+		row[14] = ""
+	} else {
+		row[14] = strconv.FormatFloat(*r.ShapeDistTraveled, 'f', -1, 64)
+	}
+	if r.Timepoint == nil {
+		row[15] = ""
+	} else {
+		row[15] = strconv.FormatInt(int64(*r.Timepoint), 10)
+	}
+	row[16] = r.PickupBookingRuleId
+	row[17] = r.DropOffBookingRuleId
+	return nil
+}
+
+func (r *Transfer) CSVColumns() []string {
+	return []string{"from_stop_id", "to_stop_id", "from_route_id", "to_route_id", "from_trip_id", "to_trip_id", "transfer_type", "min_transfer_time"}
+}
+
+func (r *Transfer) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		r.FromStopID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		r.ToStopID = s
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		r.FromRouteID = s
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		r.ToRouteID = s
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		r.FromTripID = s
+	}
+
+	if i := columns[5]; i != -1 {
+		s := record[i]
+		r.ToTripID = s
+	}
+
+	if i := columns[6]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 6, Err: csvmum.ErrRequired}
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 6, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.TransferType = int(v)
+	}
+
+	if i := columns[7]; i != -1 {
+		s := record[i]
+		if s != "" {
+			var p int
+			v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+			if err != nil {
+				return &csvmum.FieldError{Index: 7, Err: fmt.Errorf("error parsing int: %w", err)}
+			}
+			p = int(v)
+			r.MinTransferTime = &p
+		}
+	}
+
+	return nil
+}
+
+func (r *Transfer) MarshalCSVRow(row []string) error {
+	row[0] = r.FromStopID
+	row[1] = r.ToStopID
+	row[2] = r.FromRouteID
+	row[3] = r.ToRouteID
+	row[4] = r.FromTripID
+	row[5] = r.ToTripID
+	row[6] = strconv.FormatInt(int64(r.TransferType), 10)
+	if r.MinTransferTime == nil {
+		row[7] = ""
+	} else {
+		row[7] = strconv.FormatInt(int64(*r.MinTransferTime), 10)
+	}
+	return nil
+}
+
+func (r *Trip) CSVColumns() []string {
+	return []string{"route_id", "service_id", "trip_id", "trip_headsign", "trip_short_name", "direction_id", "block_id", "shape_id", "wheelchair_accessible", "bikes_allowed"}
+}
+
+func (r *Trip) UnmarshalCSVRow(record []string, columns []int) error {
+	if i := columns[0]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 0, Err: csvmum.ErrRequired}
+		}
+		r.RouteID = s
+	}
+
+	if i := columns[1]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 1, Err: csvmum.ErrRequired}
+		}
+		r.ServiceID = s
+	}
+
+	if i := columns[2]; i != -1 {
+		s := record[i]
+		if s == "" {
+			return &csvmum.FieldError{Index: 2, Err: csvmum.ErrRequired}
+		}
+		r.ID = s
+	}
+
+	if i := columns[3]; i != -1 {
+		s := record[i]
+		r.Headsign = s
+	}
+
+	if i := columns[4]; i != -1 {
+		s := record[i]
+		r.ShortName = s
+	}
+
+	if i := columns[5]; i != -1 {
+		s := record[i]
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 5, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.DirectionID = int(v)
+	}
+
+	if i := columns[6]; i != -1 {
+		s := record[i]
+		r.BlockID = s
+	}
+
+	if i := columns[7]; i != -1 {
+		s := record[i]
+		r.ShapeID = s
+	}
+
+	{
+		var s string
+		if i := columns[8]; i != -1 {
+			s = record[i]
+		}
+		if s == "" {
+			s = "0"
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 8, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.WheelchairAccessible = int(v)
+	}
+
+	{
+		var s string
+		if i := columns[9]; i != -1 {
+			s = record[i]
+		}
+		if s == "" {
+			s = "0"
+		}
+		v, err := strconv.ParseInt(s, 10, strconv.IntSize)
+		if err != nil {
+			return &csvmum.FieldError{Index: 9, Err: fmt.Errorf("error parsing int: %w", err)}
+		}
+		r.BikesAllowed = int(v)
+	}
+
+	return nil
+}
+
+func (r *Trip) MarshalCSVRow(row []string) error {
+	row[0] = r.RouteID
+	row[1] = r.ServiceID
+	row[2] = r.ID
+	row[3] = r.Headsign
+	row[4] = r.ShortName
+	row[5] = strconv.FormatInt(int64(r.DirectionID), 10)
+	row[6] = r.BlockID
+	row[7] = r.ShapeID
+	row[8] = strconv.FormatInt(int64(r.WheelchairAccessible), 10)
+	row[9] = strconv.FormatInt(int64(r.BikesAllowed), 10)
+	return nil
+}
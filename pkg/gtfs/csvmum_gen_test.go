@@ -0,0 +1,252 @@
+package gtfs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bridgelightcloud/bogie/pkg/csvmum"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ csvmum.RowUnmarshaler = (*Agency)(nil)
+	_ csvmum.RowUnmarshaler = (*Attribution)(nil)
+	_ csvmum.RowUnmarshaler = (*Calendar)(nil)
+	_ csvmum.RowUnmarshaler = (*CalendarDate)(nil)
+	_ csvmum.RowUnmarshaler = (*FareAttribute)(nil)
+	_ csvmum.RowUnmarshaler = (*FareRule)(nil)
+	_ csvmum.RowUnmarshaler = (*FeedInfo)(nil)
+	_ csvmum.RowUnmarshaler = (*Frequency)(nil)
+	_ csvmum.RowUnmarshaler = (*Level)(nil)
+	_ csvmum.RowUnmarshaler = (*Pathway)(nil)
+	_ csvmum.RowUnmarshaler = (*Route)(nil)
+	_ csvmum.RowUnmarshaler = (*ShapePoint)(nil)
+	_ csvmum.RowUnmarshaler = (*Stop)(nil)
+	_ csvmum.RowUnmarshaler = (*StopTime)(nil)
+	_ csvmum.RowUnmarshaler = (*Transfer)(nil)
+	_ csvmum.RowUnmarshaler = (*Trip)(nil)
+
+	_ csvmum.RowMarshaler = (*Agency)(nil)
+	_ csvmum.RowMarshaler = (*Attribution)(nil)
+	_ csvmum.RowMarshaler = (*Calendar)(nil)
+	_ csvmum.RowMarshaler = (*CalendarDate)(nil)
+	_ csvmum.RowMarshaler = (*FareAttribute)(nil)
+	_ csvmum.RowMarshaler = (*FareRule)(nil)
+	_ csvmum.RowMarshaler = (*FeedInfo)(nil)
+	_ csvmum.RowMarshaler = (*Frequency)(nil)
+	_ csvmum.RowMarshaler = (*Level)(nil)
+	_ csvmum.RowMarshaler = (*Pathway)(nil)
+	_ csvmum.RowMarshaler = (*Route)(nil)
+	_ csvmum.RowMarshaler = (*ShapePoint)(nil)
+	_ csvmum.RowMarshaler = (*Stop)(nil)
+	_ csvmum.RowMarshaler = (*StopTime)(nil)
+	_ csvmum.RowMarshaler = (*Transfer)(nil)
+	_ csvmum.RowMarshaler = (*Trip)(nil)
+)
+
+func TestGeneratedCodec(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, string)
+		csv  string
+	}{
+		{
+			name: "agency",
+			test: assertGeneratedMatches[Agency],
+			csv: "agency_id,agency_name,agency_url,agency_timezone,agency_lang,agency_phone,agency_fare_url,agency_email\n" +
+				"A1,Metro,https://metro.example,America/Los_Angeles,en,555-0100,https://metro.example/fares,info@metro.example\n" +
+				"A2,,https://bus.example,America/Los_Angeles,,,,\n" +
+				"A3,Bus,https://bus.example,America/Los_Angeles,,,,\n",
+		},
+		{
+			name: "attributions",
+			test: assertGeneratedMatches[Attribution],
+			csv: "attribution_id,agency_id,route_id,trip_id,organization_name,is_producer,is_operator,is_authority,attribution_url\n" +
+				"AT1,A1,,,Transit Data Co,1,,,https://data.example\n" +
+				",,R1,,Metro,,1,1,\n" +
+				"AT3,,,,,1,,,\n" +
+				"AT4,,,,City,yes,,,\n",
+		},
+		{
+			name: "calendar",
+			test: assertGeneratedMatches[Calendar],
+			csv: "service_id,monday,tuesday,wednesday,thursday,friday,saturday,sunday,start_date,end_date\n" +
+				"WK,1,1,1,1,1,0,0,20240101,20241231\n" +
+				"WE,0,0,0,0,0,1,x,20240101,20241231\n" +
+				"HOL,0,0,0,0,0,0,0,2024-01-01,20241231\n" +
+				"SUN,0,0,0,0,0,0,1,20240101,\n",
+		},
+		{
+			name: "calendar dates",
+			test: assertGeneratedMatches[CalendarDate],
+			csv: "service_id,date,exception_type\n" +
+				"WK,20240704,2\n" +
+				"WE,20240704,\n" +
+				"WE,July 4,1\n",
+		},
+		{
+			name: "fare attributes",
+			test: assertGeneratedMatches[FareAttribute],
+			csv: "fare_id,price,currency_type,payment_method,transfers,agency_id,transfer_duration\n" +
+				"F1,2.50,usd,0,,A1,5400\n" +
+				"F2,250,JPY,1,0,,\n" +
+				"F3,2.5.0,USD,0,,,\n" +
+				"F4,1.75,XYZ,0,1,,\n" +
+				"F5,1.75,EUR,,1,,\n",
+		},
+		{
+			name: "fare rules",
+			test: assertGeneratedMatches[FareRule],
+			csv: "fare_id,route_id,origin_id,destination_id,contains_id\n" +
+				"F1,R1,,,\n" +
+				"F2,,Z1,Z2,\n" +
+				",,,,Z3\n",
+		},
+		{
+			name: "feed info",
+			test: assertGeneratedMatches[FeedInfo],
+			csv: "feed_publisher_name,feed_publisher_url,feed_lang,feed_start_date,feed_end_date,feed_version\n" +
+				"Metro,https://metro.example,en,20240101,20241231,2024.1\n" +
+				"Metro,https://metro.example,en,,,\n" +
+				"Metro,https://metro.example,en,2024-01-01,,\n",
+		},
+		{
+			name: "frequencies",
+			test: assertGeneratedMatches[Frequency],
+			csv: "trip_id,start_time,end_time,headway_secs,exact_times\n" +
+				"T1,06:00:00,09:00:00,300,1\n" +
+				"T1,09:00:00,25:30:00,600,\n" +
+				"T2,06:00:00,,600,0\n" +
+				"T3,06:00:00,09:00:00,often,0\n",
+		},
+		{
+			name: "levels",
+			test: assertGeneratedMatches[Level],
+			csv: "level_id,level_index,level_name\n" +
+				"L1,0,Ground\n" +
+				"L2,-1.5,Mezzanine\n" +
+				"L3,one,Platform\n",
+		},
+		{
+			name: "pathways",
+			test: assertGeneratedMatches[Pathway],
+			csv: "pathway_id,from_stop_id,to_stop_id,pathway_mode,is_bidirectional,length,traversal_time,stair_count,max_slope,min_width,signposted_as\n" +
+				"PW1,E1,N1,1,1,25.5,20,,0.05,1.5,Platforms\n" +
+				"PW2,N1,P1,2,1,,,-20,,,\n" +
+				"PW3,N1,P1,4,0,,,,,,\n" +
+				"PW4,N1,P1,escalator,0,,,,,,\n" +
+				"PW5,N1,,5,1,,,,,,\n",
+		},
+		{
+			name: "routes",
+			test: assertGeneratedMatches[Route],
+			csv: "route_id,agency_id,route_short_name,route_long_name,route_type,route_color,route_text_color\n" +
+				"R1,A1,1,Main Street,3,FF0000,FFFFFF\n" +
+				"R2,A1,2,Broadway,,,\n" +
+				"R3,A1,3,Sunset,3, 00ff7f ,000000\n" +
+				"R4,A1,4,Vermont,3,green,\n",
+		},
+		{
+			name: "shapes",
+			test: assertGeneratedMatches[ShapePoint],
+			csv: "shape_id,shape_pt_lat,shape_pt_lon,shape_pt_sequence,shape_dist_traveled\n" +
+				"SH1,34.05,-118.25,1,0\n" +
+				"SH1,34.06,-118.24,2,\n" +
+				"SH1,34.07,-118.23,,2.5\n" +
+				"SH1,north,-118.22,4,3\n",
+		},
+		{
+			name: "stops",
+			test: assertGeneratedMatches[Stop],
+			csv: "stop_id,stop_name,stop_lat,stop_lon,location_type,parent_station\n" +
+				"S1,Main St,34.05,-118.25,1,\n" +
+				"S2,Main St Platform,34.05,-118.25,,S1\n" +
+				"S3,Broadway,34.06,-118.24,station,\n",
+		},
+		{
+			name: "stops without location type",
+			test: assertGeneratedMatches[Stop],
+			csv: "stop_id,stop_name\n" +
+				"S1,Main St\n",
+		},
+		{
+			name: "stop times",
+			test: assertGeneratedMatches[StopTime],
+			csv: "trip_id,arrival_time,departure_time,stop_id,stop_sequence,pickup_type,drop_off_type,shape_dist_traveled,timepoint\n" +
+				"T1,08:00:00,08:00:30,S1,1,0,0,0,1\n" +
+				"T1,25:10:00,25:10:00,S2,2,,,1.25,\n" +
+				"T1,08:20:00,08:20:00,S3,3,x,,,\n" +
+				"T1,8am,08:30:00,S4,4,,,,\n" +
+				"T1,08:40:00,08:40:00,S5,,,,,\n",
+		},
+		{
+			name: "transfers",
+			test: assertGeneratedMatches[Transfer],
+			csv: "from_stop_id,to_stop_id,from_route_id,to_route_id,from_trip_id,to_trip_id,transfer_type,min_transfer_time\n" +
+				"S1,S2,,,,,2,180\n" +
+				"S1,S2,R1,R2,,,1,\n" +
+				",,,,T1,T2,4,\n" +
+				"S1,S3,,,,,,\n" +
+				"S1,S4,,,,,0,soon\n",
+		},
+		{
+			name: "trips",
+			test: assertGeneratedMatches[Trip],
+			csv: "route_id,service_id,trip_id,direction_id,wheelchair_accessible,bikes_allowed\n" +
+				"R1,WK,T1,0,1,2\n" +
+				"R1,WK,T2,1,,\n" +
+				"R1,WK,T3,north,,\n" +
+				",WK,T4,0,,\n",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.test(t, tc.csv)
+		})
+	}
+}
+
+// assertGeneratedMatches checks that the generated methods on T decode and
+// encode exactly as the reflection codec does.
+func assertGeneratedMatches[T any](t *testing.T, input string) {
+	assert := assert.New(t)
+
+	generated, generatedErr := readAll[T](input, csvmum.ContinueOnError())
+	reflected, reflectedErr := readAll[T](input, csvmum.ContinueOnError(), csvmum.DisableGenerated())
+
+	assert.Equal(reflected, generated)
+	if reflectedErr == nil {
+		assert.NoError(generatedErr)
+	} else {
+		assert.EqualError(generatedErr, reflectedErr.Error())
+	}
+
+	assert.Equal(writeAll(reflected, csvmum.DisableGenerated()), writeAll(generated))
+}
+
+func readAll[T any](input string, opts ...csvmum.Option) ([]T, error) {
+	um, err := csvmum.NewUnmarshaler[T](bytes.NewBufferString(input), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return um.ReadAll()
+}
+
+func writeAll[T any](records []T, opts ...csvmum.Option) string {
+	b := &bytes.Buffer{}
+	m, err := csvmum.NewMarshaler[T](b, opts...)
+	if err != nil {
+		return err.Error()
+	}
+	for _, r := range records {
+		if err := m.Marshal(r); err != nil {
+			return err.Error()
+		}
+	}
+	m.Flush()
+	return b.String()
+}
@@ -0,0 +1,210 @@
+package gtfs
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+type FareAttribute struct {
+	FareID           string  `json:"fareId" csv:"fare_id,required"`
+	Price            Decimal `json:"price" csv:"price,required"`
+	CurrencyType     string  `json:"currencyType" csv:"currency_type,required,parse=currency"`
+	PaymentMethod    int     `json:"paymentMethod" csv:"payment_method,required"`
+	Transfers        *int    `json:"transfers" csv:"transfers"`
+	AgencyID         string  `json:"agencyId,omitempty" csv:"agency_id"`
+	TransferDuration *int    `json:"transferDuration,omitempty" csv:"transfer_duration"`
+}
+
+func (f FareAttribute) key() string {
+	return f.FareID
+}
+
+func (f FareAttribute) validate() errorList {
+	var errs errorList
+
+	if f.Price.IsNegative() {
+		errs.add(fmt.Errorf("price must be greater than or equal to 0"))
+	}
+	if _, err := NewMoney(f.Price, f.CurrencyType); err != nil {
+		errs.add(fmt.Errorf("invalid price: %w", err))
+	}
+	if f.PaymentMethod < PaymentMethod.L || f.PaymentMethod > PaymentMethod.U {
+		errs.add(fmt.Errorf("invalid payment method: %d", f.PaymentMethod))
+	}
+	if f.Transfers != nil && (*f.Transfers < FareTransfers.L || *f.Transfers > FareTransfers.U) {
+		errs.add(fmt.Errorf("invalid transfers: %d", *f.Transfers))
+	}
+	if f.TransferDuration != nil && *f.TransferDuration < 0 {
+		errs.add(fmt.Errorf("transfer duration must be greater than or equal to 0"))
+	}
+
+	return errs
+}
+
+func (f FareAttribute) AfterUnmarshalCSV() error {
+	return f.validate().err()
+}
+
+// Amount returns the price of f in its currency. The price of a fare that
+// failed validation may be wrong.
+func (f FareAttribute) Amount() Money {
+	m, _ := NewMoney(f.Price, f.CurrencyType)
+	return m
+}
+
+type FareRule struct {
+	FareID        string `json:"fareId" csv:"fare_id,required"`
+	RouteID       string `json:"routeId,omitempty" csv:"route_id"`
+	OriginID      string `json:"originId,omitempty" csv:"origin_id"`
+	DestinationID string `json:"destinationId,omitempty" csv:"destination_id"`
+	ContainsID    string `json:"containsId,omitempty" csv:"contains_id"`
+}
+
+func (r FareRule) key() string {
+	return fmt.Sprintf("%s-%s-%s-%s-%s", r.FareID, r.RouteID, r.OriginID, r.DestinationID, r.ContainsID)
+}
+
+func (r FareRule) validate() errorList {
+	var errs errorList
+	return errs
+}
+
+func (r FareRule) AfterUnmarshalCSV() error {
+	return r.validate().err()
+}
+
+// FareQuery describes a ride to find the fares of.
+type FareQuery struct {
+	RouteID string
+	// OriginZone and DestinationZone are the fare zones of the stops the ride
+	// starts and ends at.
+	OriginZone      string
+	DestinationZone string
+	// Zones are the fare zones the ride passes through, including those of
+	// its origin and destination.
+	Zones []string
+}
+
+// fareConditions are the conditions the rules of a fare put on rides.
+type fareConditions struct {
+	routes   map[string]bool
+	pairs    []FareRule
+	contains map[string]bool
+}
+
+func (c fareConditions) match(q FareQuery) bool {
+	if len(c.routes) > 0 && !c.routes[q.RouteID] {
+		return false
+	}
+
+	if len(c.pairs) > 0 && !slices.ContainsFunc(c.pairs, func(r FareRule) bool {
+		return matchID(r.OriginID, q.OriginZone) && matchID(r.DestinationID, q.DestinationZone)
+	}) {
+		return false
+	}
+
+	if len(c.contains) > 0 {
+		zones := make(map[string]bool, len(q.Zones))
+		for _, z := range q.Zones {
+			zones[z] = true
+		}
+		if !maps.Equal(zones, c.contains) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Fares returns the fares that apply to the ride q, cheapest first. A fare
+// without rules applies to every ride. Otherwise its rules restrict the
+// routes it applies to, the pairs of origin and destination zones, and the
+// zones passed through, which must be exactly those of its contains rules.
+func (s GTFSSchedule) Fares(q FareQuery) []FareAttribute {
+	conditions := make(map[string]*fareConditions)
+	for _, r := range s.FareRules {
+		c := conditions[r.FareID]
+		if c == nil {
+			c = &fareConditions{routes: map[string]bool{}, contains: map[string]bool{}}
+			conditions[r.FareID] = c
+		}
+
+		if r.RouteID != "" {
+			c.routes[r.RouteID] = true
+		}
+		if r.OriginID != "" || r.DestinationID != "" {
+			c.pairs = append(c.pairs, r)
+		}
+		if r.ContainsID != "" {
+			c.contains[r.ContainsID] = true
+		}
+	}
+
+	var fares []FareAttribute
+	for _, f := range s.FareAttributes {
+		if c := conditions[f.FareID]; c == nil || c.match(q) {
+			fares = append(fares, f)
+		}
+	}
+
+	slices.SortFunc(fares, func(a, b FareAttribute) int {
+		return cmp.Or(
+			cmp.Compare(a.CurrencyType, b.CurrencyType),
+			cmp.Compare(a.Amount().Minor, b.Amount().Minor),
+			cmp.Compare(a.FareID, b.FareID),
+		)
+	})
+
+	return fares
+}
+
+// RouteFares returns the fares that apply to rides on the route with ID
+// routeID between the zones of the stops with IDs from and to, cheapest first.
+func (s GTFSSchedule) RouteFares(routeID, from, to string) []FareAttribute {
+	origin, destination := s.Stops[from].ZoneID, s.Stops[to].ZoneID
+
+	q := FareQuery{RouteID: routeID, OriginZone: origin, DestinationZone: destination}
+	for _, z := range []string{origin, destination} {
+		if z != "" && !slices.Contains(q.Zones, z) {
+			q.Zones = append(q.Zones, z)
+		}
+	}
+
+	return s.Fares(q)
+}
+
+// validateFares checks that fares refer to agencies, and fare rules to fares,
+// routes and the zones of stops.
+func (s *GTFSSchedule) validateFares() {
+	for _, id := range slices.Sorted(maps.Keys(s.FareAttributes)) {
+		f := s.FareAttributes[id]
+		if _, ok := s.Agencies[f.AgencyID]; f.AgencyID != "" && !ok {
+			s.errors.add(fmt.Errorf("fare_attributes.txt: fare %s: unknown agency ID: %s", id, f.AgencyID))
+		}
+	}
+
+	zones := make(map[string]bool)
+	for _, stop := range s.Stops {
+		if stop.ZoneID != "" {
+			zones[stop.ZoneID] = true
+		}
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(s.FareRules)) {
+		r := s.FareRules[k]
+
+		if _, ok := s.FareAttributes[r.FareID]; !ok {
+			s.errors.add(fmt.Errorf("fare_rules.txt: unknown fare ID: %s", r.FareID))
+		}
+		if _, ok := s.Routes[r.RouteID]; r.RouteID != "" && !ok {
+			s.errors.add(fmt.Errorf("fare_rules.txt: fare %s: unknown route ID: %s", r.FareID, r.RouteID))
+		}
+		for _, z := range []string{r.OriginID, r.DestinationID, r.ContainsID} {
+			if z != "" && !zones[z] {
+				s.errors.add(fmt.Errorf("fare_rules.txt: fare %s: unknown zone ID: %s", r.FareID, z))
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package gtfs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFareAttributes(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	b := bytes.NewBufferString("fare_id,price,currency_type,payment_method,transfers,agency_id,transfer_duration\n" +
+		"F1,2.50,usd,0,,A1,5400\n" +
+		"F2,250,JPY,1,0,,\n" +
+		"F3,2.505,USD,0,,,\n" +
+		"F4,2.5,JPY,0,,,\n" +
+		"F5,-1,USD,2,3,,-60\n")
+
+	records := map[string]FareAttribute{}
+	var errs, warnings errorList
+	parse(b, records, &errs, &warnings)
+
+	duration, none := 5400, 0
+	assert.Equal([]string{
+		"invalid record: invalid price: amount 2.505 has more than 2 decimal places for USD",
+		"invalid record: invalid price: amount 2.5 has more than 0 decimal places for JPY",
+		"invalid record: price must be greater than or equal to 0",
+		"invalid record: invalid payment method: 2",
+		"invalid record: invalid transfers: 3",
+		"invalid record: transfer duration must be greater than or equal to 0",
+	}, errorStrings(errs))
+	assert.Equal(map[string]FareAttribute{
+		"F1": {FareID: "F1", Price: NewDecimal(250, 2), CurrencyType: "USD", PaymentMethod: PayOnBoard, AgencyID: "A1", TransferDuration: &duration},
+		"F2": {FareID: "F2", Price: NewDecimal(250, 0), CurrencyType: "JPY", PaymentMethod: PayBeforeBoarding, Transfers: &none},
+	}, records)
+
+	assert.Equal(Money{Minor: 250, Currency: "USD"}, records["F1"].Amount())
+	assert.Equal(Money{Minor: 250, Currency: "JPY"}, records["F2"].Amount())
+}
+
+func TestFares(t *testing.T) {
+	t.Parallel()
+
+	s := GTFSSchedule{
+		Stops: map[string]Stop{
+			"S1": {ID: "S1", ZoneID: "Z1"},
+			"S2": {ID: "S2", ZoneID: "Z1"},
+			"S3": {ID: "S3", ZoneID: "Z2"},
+			"S4": {ID: "S4"},
+		},
+		FareAttributes: map[string]FareAttribute{
+			"local":   {FareID: "local", Price: NewDecimal(175, 2), CurrencyType: "USD"},
+			"express": {FareID: "express", Price: NewDecimal(35, 1), CurrencyType: "USD"},
+			"z1z2":    {FareID: "z1z2", Price: NewDecimal(275, 2), CurrencyType: "USD"},
+			"through": {FareID: "through", Price: NewDecimal(3, 0), CurrencyType: "USD"},
+			"any":     {FareID: "any", Price: NewDecimal(5, 0), CurrencyType: "USD"},
+		},
+		FareRules: map[string]FareRule{},
+	}
+	for _, r := range []FareRule{
+		{FareID: "local", RouteID: "R1"},
+		{FareID: "local", RouteID: "R2"},
+		{FareID: "express", RouteID: "X1"},
+		{FareID: "z1z2", OriginID: "Z1", DestinationID: "Z2"},
+		{FareID: "z1z2", OriginID: "Z2", DestinationID: "Z1"},
+		{FareID: "through", ContainsID: "Z1"},
+		{FareID: "through", ContainsID: "Z2"},
+		{FareID: "through", ContainsID: "Z3"},
+	} {
+		s.FareRules[r.key()] = r
+	}
+
+	testCases := map[string]struct {
+		query FareQuery
+		fares []string
+	}{
+		"route": {
+			query: FareQuery{RouteID: "R2"},
+			fares: []string{"local", "any"},
+		},
+		"other route": {
+			query: FareQuery{RouteID: "X1"},
+			fares: []string{"express", "any"},
+		},
+		"zones": {
+			query: FareQuery{RouteID: "R9", OriginZone: "Z2", DestinationZone: "Z1", Zones: []string{"Z2", "Z1"}},
+			fares: []string{"z1z2", "any"},
+		},
+		"zones passed through": {
+			query: FareQuery{RouteID: "R9", OriginZone: "Z1", DestinationZone: "Z3", Zones: []string{"Z1", "Z2", "Z3", "Z2"}},
+			fares: []string{"through", "any"},
+		},
+		"some zones passed through": {
+			query: FareQuery{RouteID: "R9", OriginZone: "Z1", DestinationZone: "Z2", Zones: []string{"Z1", "Z2"}},
+			fares: []string{"z1z2", "any"},
+		},
+		"no rules": {
+			query: FareQuery{},
+			fares: []string{"any"},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			fares := []string{}
+			for _, f := range s.Fares(tc.query) {
+				fares = append(fares, f.FareID)
+			}
+
+			assert.Equal(tc.fares, fares)
+		})
+	}
+
+	t.Run("route fares", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		fares := []string{}
+		for _, f := range s.RouteFares("R1", "S1", "S3") {
+			fares = append(fares, f.FareID)
+		}
+		assert.Equal([]string{"local", "z1z2", "any"}, fares)
+	})
+}
+
+func TestValidateFares(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s := GTFSSchedule{
+		Agencies: map[string]Agency{"A1": {ID: "A1"}},
+		Routes:   map[string]Route{"R1": {ID: "R1"}},
+		Stops:    map[string]Stop{"S1": {ID: "S1", ZoneID: "Z1"}},
+		FareAttributes: map[string]FareAttribute{
+			"F1": {FareID: "F1", AgencyID: "A1"},
+			"F2": {FareID: "F2", AgencyID: "A9"},
+		},
+		FareRules: map[string]FareRule{
+			"F1-R1---":   {FareID: "F1", RouteID: "R1"},
+			"F1-R9-Z1--": {FareID: "F1", RouteID: "R9", OriginID: "Z1"},
+			"F1---Z9-":   {FareID: "F1", DestinationID: "Z9"},
+			"F9----":     {FareID: "F9"},
+		},
+	}
+
+	s.validate()
+
+	assert.Equal([]string{
+		"fare_attributes.txt: fare F2: unknown agency ID: A9",
+		"fare_rules.txt: fare F1: unknown zone ID: Z9",
+		"fare_rules.txt: fare F1: unknown route ID: R9",
+		"fare_rules.txt: unknown fare ID: F9",
+	}, errorStrings(s.errors))
+}
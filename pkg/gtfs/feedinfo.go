@@ -0,0 +1,168 @@
+package gtfs
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+type FeedInfo struct {
+	PublisherName string `json:"feedPublisherName" csv:"feed_publisher_name,required"`
+	PublisherURL  string `json:"feedPublisherUrl" csv:"feed_publisher_url,required"`
+	Lang          string `json:"feedLang" csv:"feed_lang,required"`
+	DefaultLang   string `json:"defaultLang,omitempty" csv:"default_lang"`
+	StartDate     *Date  `json:"feedStartDate,omitempty" csv:"feed_start_date"`
+	EndDate       *Date  `json:"feedEndDate,omitempty" csv:"feed_end_date"`
+	Version       string `json:"feedVersion,omitempty" csv:"feed_version"`
+	ContactEmail  string `json:"feedContactEmail,omitempty" csv:"feed_contact_email"`
+	ContactURL    string `json:"feedContactUrl,omitempty" csv:"feed_contact_url"`
+}
+
+func (fi FeedInfo) key() string {
+	return fmt.Sprintf("%s-%s", fi.PublisherName, fi.Version)
+}
+
+func (fi FeedInfo) validate() errorList {
+	var errs errorList
+
+	if fi.StartDate != nil && fi.EndDate != nil && fi.EndDate.Before(fi.StartDate.Time) {
+		errs.add(fmt.Errorf("feed end date must not be before feed start date"))
+	}
+
+	return errs
+}
+
+func (fi FeedInfo) AfterUnmarshalCSV() error {
+	return fi.validate().err()
+}
+
+// setFeedInfo sets the feed info of the schedule from the records of
+// feed_info.txt, which should have exactly one.
+func (s *GTFSSchedule) setFeedInfo(records map[string]FeedInfo) {
+	if len(records) == 0 {
+		return
+	}
+	if len(records) > 1 {
+		s.errors.add(fmt.Errorf("feed_info.txt: expected 1 record, found %d", len(records)))
+	}
+
+	fi := records[slices.Min(slices.Collect(maps.Keys(records)))]
+	s.FeedInfo = &fi
+}
+
+// ServiceWindow is the span of dates a schedule is in effect, inclusive.
+type ServiceWindow struct {
+	Start Date `json:"start"`
+	End   Date `json:"end"`
+}
+
+// Contains reports whether the date of t, in its location, is in w.
+func (w ServiceWindow) Contains(t time.Time) bool {
+	d := dateOf(t)
+	return !d.Before(w.Start.Time) && !d.After(w.End.Time)
+}
+
+// ServiceWindow returns the span of dates the schedule has service on: from
+// the first date of calendar.txt or calendar_dates.txt with service to the
+// last, limited to the feed start and end dates of feed_info.txt. Calendars
+// only count from their first to their last date on a weekday they run,
+// leaving out dates removed by calendar_dates.txt. Without calendars, it is
+// the span of the feed dates. It reports false if there are no dates to go
+// on, or no service within the feed dates.
+func (s GTFSSchedule) ServiceWindow() (ServiceWindow, bool) {
+	var start, end time.Time
+	extend := func(from, to time.Time) {
+		if start.IsZero() || from.Before(start) {
+			start = from
+		}
+		if end.IsZero() || to.After(end) {
+			end = to
+		}
+	}
+
+	removed := make(map[serviceDay]bool)
+	for _, cd := range s.CalendarDates {
+		switch cd.ExceptionType {
+		case Added:
+			extend(cd.Date.Time, cd.Date.Time)
+		case Removed:
+			removed[serviceDay{cd.ServiceID, cd.Date.Format(dateFormat)}] = true
+		}
+	}
+	for _, c := range s.Calendar {
+		if first, last, ok := serviceDates(c, removed); ok {
+			extend(first, last)
+		}
+	}
+
+	if fi := s.FeedInfo; fi != nil {
+		if fi.StartDate != nil && (start.IsZero() || fi.StartDate.After(start)) {
+			start = fi.StartDate.Time
+		}
+		if fi.EndDate != nil && (end.IsZero() || fi.EndDate.Before(end)) {
+			end = fi.EndDate.Time
+		}
+	}
+
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return ServiceWindow{}, false
+	}
+
+	return ServiceWindow{Start: Date{start}, End: Date{end}}, true
+}
+
+// serviceDay is a date of a service, formatted as in calendar_dates.txt.
+type serviceDay struct {
+	serviceID string
+	date      string
+}
+
+// serviceDates returns the first and last dates of c on a weekday it runs
+// that are not removed. It reports false if there are none.
+func serviceDates(c Calendar, removed map[serviceDay]bool) (time.Time, time.Time, bool) {
+	runs := func(d time.Time) bool {
+		return c.runsOn(d.Weekday()) && !removed[serviceDay{c.ServiceID, d.Format(dateFormat)}]
+	}
+
+	first := c.StartDate.Time
+	for !first.After(c.EndDate.Time) && !runs(first) {
+		first = first.AddDate(0, 0, 1)
+	}
+	if first.After(c.EndDate.Time) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	last := c.EndDate.Time
+	for !runs(last) {
+		last = last.AddDate(0, 0, -1)
+	}
+	return first, last, true
+}
+
+// checkExpiry warns if the service window of the schedule has ended by now,
+// or ends within horizon of it. Without a service window, it goes by the
+// feed end date of feed_info.txt alone.
+func (s *GTFSSchedule) checkExpiry(now time.Time, horizon time.Duration) {
+	w, ok := s.ServiceWindow()
+	if !ok {
+		if s.FeedInfo == nil || s.FeedInfo.EndDate == nil {
+			return
+		}
+		w.End = *s.FeedInfo.EndDate
+	}
+
+	end, _ := w.End.MarshalText()
+	today := dateOf(now)
+	switch {
+	case today.After(w.End.Time):
+		s.warnings.add(fmt.Errorf("feed expired: service ended on %s", end))
+	case dateOf(now.Add(horizon)).After(w.End.Time):
+		s.warnings.add(fmt.Errorf("feed expires soon: service ends on %s", end))
+	}
+}
+
+// dateOf returns the date of t, in its location, as a Date would hold it.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
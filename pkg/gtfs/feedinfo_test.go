@@ -0,0 +1,245 @@
+package gtfs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// date returns the Date of a GTFS date value, such as 20240101.
+func date(s string) Date {
+	var d Date
+	if err := d.UnmarshalText([]byte(s)); err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestParseFeedInfo(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	b := bytes.NewBufferString("feed_publisher_name,feed_publisher_url,feed_lang,feed_start_date,feed_end_date,feed_version\n" +
+		"Metro,https://metro.example,en,20240101,20241231,2024.1\n" +
+		"Metro,https://metro.example,en,20241231,20240101,2024.2\n")
+
+	records := map[string]FeedInfo{}
+	var errs, warnings errorList
+	parse(b, records, &errs, &warnings)
+
+	start, end := date("20240101"), date("20241231")
+	assert.Equal([]string{
+		"invalid record: feed end date must not be before feed start date",
+	}, errorStrings(errs))
+	assert.Equal(map[string]FeedInfo{
+		"Metro-2024.1": {PublisherName: "Metro", PublisherURL: "https://metro.example", Lang: "en", StartDate: &start, EndDate: &end, Version: "2024.1"},
+	}, records)
+}
+
+func TestSetFeedInfo(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	var s GTFSSchedule
+	s.setFeedInfo(map[string]FeedInfo{})
+	assert.Nil(s.FeedInfo)
+
+	s.setFeedInfo(map[string]FeedInfo{
+		"Metro-2": {PublisherName: "Metro", Version: "2"},
+		"Metro-1": {PublisherName: "Metro", Version: "1"},
+	})
+	if assert.NotNil(s.FeedInfo) {
+		assert.Equal("1", s.FeedInfo.Version)
+	}
+	assert.Equal([]string{"feed_info.txt: expected 1 record, found 2"}, errorStrings(s.errors))
+}
+
+func TestServiceWindow(t *testing.T) {
+	t.Parallel()
+
+	feedInfo := func(start, end string) *FeedInfo {
+		fi := FeedInfo{PublisherName: "Metro"}
+		if start != "" {
+			d := date(start)
+			fi.StartDate = &d
+		}
+		if end != "" {
+			d := date(end)
+			fi.EndDate = &d
+		}
+		return &fi
+	}
+
+	// weekdays run from Friday 20240301 to Friday 20240628, and weekends
+	// from Saturday 20240406 to Sunday 20240728
+	calendar := map[string]Calendar{
+		"WK": {ServiceID: "WK", Monday: 1, Tuesday: 1, Wednesday: 1, Thursday: 1, Friday: 1, StartDate: date("20240301"), EndDate: date("20240630")},
+		"WE": {ServiceID: "WE", Saturday: 1, Sunday: 1, StartDate: date("20240401"), EndDate: date("20240731")},
+		"NO": {ServiceID: "NO", StartDate: date("20230101"), EndDate: date("20251231")},
+	}
+	calendarDates := map[string]CalendarDate{
+		"WK-20240220": {ServiceID: "WK", Date: date("20240220"), ExceptionType: Added},
+		"WK-20240901": {ServiceID: "WK", Date: date("20240901"), ExceptionType: Removed},
+	}
+
+	testCases := map[string]struct {
+		schedule   GTFSSchedule
+		start, end string
+		ok         bool
+	}{
+		"calendar": {
+			schedule: GTFSSchedule{Calendar: calendar},
+			start:    "20240301", end: "20240728", ok: true,
+		},
+		"calendar dates": {
+			schedule: GTFSSchedule{Calendar: calendar, CalendarDates: calendarDates},
+			start:    "20240220", end: "20240728", ok: true,
+		},
+		"removed dates": {
+			schedule: GTFSSchedule{
+				Calendar: map[string]Calendar{"WK": calendar["WK"]},
+				CalendarDates: map[string]CalendarDate{
+					"WK-20240301": {ServiceID: "WK", Date: date("20240301"), ExceptionType: Removed},
+					"WK-20240628": {ServiceID: "WK", Date: date("20240628"), ExceptionType: Removed},
+				},
+			},
+			start: "20240304", end: "20240627", ok: true,
+		},
+		"no service days": {
+			schedule: GTFSSchedule{Calendar: map[string]Calendar{"NO": calendar["NO"]}},
+		},
+		"only calendar dates": {
+			schedule: GTFSSchedule{CalendarDates: calendarDates},
+			start:    "20240220", end: "20240220", ok: true,
+		},
+		"limited by feed dates": {
+			schedule: GTFSSchedule{Calendar: calendar, FeedInfo: feedInfo("20240315", "20240630")},
+			start:    "20240315", end: "20240630", ok: true,
+		},
+		"wider feed dates": {
+			schedule: GTFSSchedule{Calendar: calendar, FeedInfo: feedInfo("20240101", "20241231")},
+			start:    "20240301", end: "20240728", ok: true,
+		},
+		"only feed dates": {
+			schedule: GTFSSchedule{FeedInfo: feedInfo("20240101", "20241231")},
+			start:    "20240101", end: "20241231", ok: true,
+		},
+		"open ended": {
+			schedule: GTFSSchedule{FeedInfo: feedInfo("20240101", "")},
+		},
+		"no service within feed dates": {
+			schedule: GTFSSchedule{Calendar: calendar, FeedInfo: feedInfo("20240801", "")},
+		},
+		"empty": {},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			w, ok := tc.schedule.ServiceWindow()
+
+			assert.Equal(tc.ok, ok)
+			if tc.ok {
+				assert.Equal(ServiceWindow{Start: date(tc.start), End: date(tc.end)}, w)
+			}
+		})
+	}
+
+	t.Run("contains", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		w := ServiceWindow{Start: date("20240301"), End: date("20240731")}
+		la, _ := time.LoadLocation("America/Los_Angeles")
+
+		assert.True(w.Contains(time.Date(2024, 3, 1, 0, 0, 0, 0, la)))
+		assert.True(w.Contains(time.Date(2024, 7, 31, 23, 59, 0, 0, la)))
+		assert.False(w.Contains(time.Date(2024, 8, 1, 0, 0, 0, 0, la)))
+		assert.False(w.Contains(time.Date(2024, 2, 29, 23, 59, 0, 0, la)))
+	})
+}
+
+func TestCheckExpiry(t *testing.T) {
+	t.Parallel()
+
+	s := GTFSSchedule{
+		Calendar: map[string]Calendar{
+			"WK": {ServiceID: "WK", Monday: 1, Tuesday: 1, Wednesday: 1, Thursday: 1, Friday: 1, Saturday: 1, Sunday: 1, StartDate: date("20240101"), EndDate: date("20240630")},
+		},
+	}
+
+	testCases := map[string]struct {
+		now      time.Time
+		horizon  time.Duration
+		warnings []string
+	}{
+		"in effect": {
+			now:      time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			horizon:  defaultExpiryHorizon,
+			warnings: []string{},
+		},
+		"last day": {
+			now:      time.Date(2024, 6, 30, 23, 0, 0, 0, time.UTC),
+			horizon:  0,
+			warnings: []string{},
+		},
+		"expires soon": {
+			now:      time.Date(2024, 6, 25, 12, 0, 0, 0, time.UTC),
+			horizon:  defaultExpiryHorizon,
+			warnings: []string{"feed expires soon: service ends on 20240630"},
+		},
+		"longer horizon": {
+			now:      time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+			horizon:  30 * 24 * time.Hour,
+			warnings: []string{"feed expires soon: service ends on 20240630"},
+		},
+		"expired": {
+			now:      time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			horizon:  defaultExpiryHorizon,
+			warnings: []string{"feed expired: service ended on 20240630"},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			s := s
+			s.checkExpiry(tc.now, tc.horizon)
+
+			assert.Equal(tc.warnings, errorStrings(s.warnings))
+		})
+	}
+
+	t.Run("expired before service", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		// the feed end date precedes the calendar, so there is no service
+		// window
+		end := date("20231231")
+		s := s
+		s.FeedInfo = &FeedInfo{PublisherName: "Metro", EndDate: &end}
+		s.checkExpiry(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), defaultExpiryHorizon)
+
+		assert.Equal([]string{"feed expired: service ended on 20231231"}, errorStrings(s.warnings))
+	})
+
+	t.Run("options", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+		o := buildScheduleOptions([]ScheduleOption{AsOf(now), WithExpiryHorizon(time.Hour)})
+
+		assert.Equal(scheduleOptions{now: now, expiryHorizon: time.Hour}, o)
+		assert.Equal(defaultExpiryHorizon, buildScheduleOptions(nil).expiryHorizon)
+	})
+}
@@ -0,0 +1,208 @@
+package gtfs
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"time"
+)
+
+type Frequency struct {
+	TripID      string `json:"tripId" csv:"trip_id,required"`
+	StartTime   Time   `json:"startTime" csv:"start_time,required"`
+	EndTime     Time   `json:"endTime" csv:"end_time,required"`
+	HeadwaySecs int    `json:"headwaySecs" csv:"headway_secs,required"`
+	ExactTimes  int    `json:"exactTimes,omitempty" csv:"exact_times,default=0"`
+}
+
+func (f Frequency) key() string {
+	start, _ := f.StartTime.MarshalText()
+	return fmt.Sprintf("%s-%s", f.TripID, start)
+}
+
+func (f Frequency) validate() errorList {
+	var errs errorList
+
+	if f.HeadwaySecs <= 0 {
+		errs.add(fmt.Errorf("headway must be greater than 0"))
+	}
+	if !f.EndTime.After(f.StartTime.Time) {
+		errs.add(fmt.Errorf("end time must be after start time"))
+	}
+	if f.ExactTimes < ExactTimes.L || f.ExactTimes > ExactTimes.U {
+		errs.add(fmt.Errorf("invalid exact times: %d", f.ExactTimes))
+	}
+
+	return errs
+}
+
+func (f Frequency) AfterUnmarshalCSV() error {
+	return f.validate().err()
+}
+
+// TripInstance is one run of a trip: either a trip with its own stop times,
+// or one of the runs of a frequency-based trip, whose stop times are those of
+// the trip shifted by Offset.
+type TripInstance struct {
+	Trip Trip `json:"trip"`
+	// Start is the departure time from the first stop.
+	Start Time `json:"start"`
+	// Offset is how far the stop times of the trip are shifted.
+	Offset time.Duration `json:"offset"`
+	// Exact reports whether Start is scheduled, rather than an estimate from a
+	// headway that riders are not told the departures of.
+	Exact     bool       `json:"exact"`
+	StopTimes []StopTime `json:"stopTimes"`
+}
+
+// TripStopTimes returns the stop times of trip t, in sequence order.
+func (s GTFSSchedule) TripStopTimes(t Trip) []StopTime {
+	var stopTimes []StopTime
+	for _, st := range s.StopTimes {
+		if st.TripID == t.ID {
+			stopTimes = append(stopTimes, st)
+		}
+	}
+	sortStopTimes(stopTimes)
+	return stopTimes
+}
+
+// TripFrequencies returns the frequencies of trip t, in start time order. It
+// is empty unless t is a frequency-based trip.
+func (s GTFSSchedule) TripFrequencies(t Trip) []Frequency {
+	var frequencies []Frequency
+	for _, f := range s.Frequencies {
+		if f.TripID == t.ID {
+			frequencies = append(frequencies, f)
+		}
+	}
+	sortFrequencies(frequencies)
+	return frequencies
+}
+
+// ExpandTrip returns the runs of trip t. A trip without frequencies has a
+// single run, on its own stop times. A frequency-based trip has a run every
+// headway from the start time of each of its frequencies until before the end
+// time, each starting from the first stop at that time. It returns nil if t
+// has no stop times, or if its first stop time has neither an arrival nor a
+// departure time, since its runs cannot then be placed.
+func (s GTFSSchedule) ExpandTrip(t Trip) []TripInstance {
+	return expandTrip(t, s.TripStopTimes(t), s.TripFrequencies(t))
+}
+
+// TripInstances returns an iterator over the runs of every trip, as
+// ExpandTrip returns them, in trip ID order.
+func (s GTFSSchedule) TripInstances() iter.Seq[TripInstance] {
+	return func(yield func(TripInstance) bool) {
+		stopTimes := make(map[string][]StopTime)
+		for _, st := range s.StopTimes {
+			stopTimes[st.TripID] = append(stopTimes[st.TripID], st)
+		}
+		frequencies := make(map[string][]Frequency)
+		for _, f := range s.Frequencies {
+			frequencies[f.TripID] = append(frequencies[f.TripID], f)
+		}
+
+		for _, id := range slices.Sorted(maps.Keys(s.Trips)) {
+			sortStopTimes(stopTimes[id])
+			sortFrequencies(frequencies[id])
+
+			for _, ti := range expandTrip(s.Trips[id], stopTimes[id], frequencies[id]) {
+				if !yield(ti) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func expandTrip(t Trip, stopTimes []StopTime, frequencies []Frequency) []TripInstance {
+	if len(stopTimes) == 0 {
+		return nil
+	}
+
+	first := stopTimes[0].DepartureTime
+	if first.IsZero() {
+		first = stopTimes[0].ArrivalTime
+	}
+	if first.IsZero() {
+		return nil
+	}
+
+	if len(frequencies) == 0 {
+		return []TripInstance{{Trip: t, Start: first, Exact: true, StopTimes: stopTimes}}
+	}
+
+	var instances []TripInstance
+	for _, f := range frequencies {
+		if f.HeadwaySecs <= 0 {
+			continue
+		}
+		headway := time.Duration(f.HeadwaySecs) * time.Second
+
+		for start := f.StartTime.Time; start.Before(f.EndTime.Time); start = start.Add(headway) {
+			offset := start.Sub(first.Time)
+			instances = append(instances, TripInstance{
+				Trip:      t,
+				Start:     Time{start},
+				Offset:    offset,
+				Exact:     f.ExactTimes == ScheduleBased,
+				StopTimes: shiftStopTimes(stopTimes, offset),
+			})
+		}
+	}
+
+	return instances
+}
+
+// shiftStopTimes returns a copy of stopTimes with every time shifted by d.
+func shiftStopTimes(stopTimes []StopTime, d time.Duration) []StopTime {
+	shifted := make([]StopTime, len(stopTimes))
+	for i, st := range stopTimes {
+		st.ArrivalTime = st.ArrivalTime.add(d)
+		st.DepartureTime = st.DepartureTime.add(d)
+		st.StartPickupDropOffWindow = st.StartPickupDropOffWindow.add(d)
+		st.EndPickupDropOffWindow = st.EndPickupDropOffWindow.add(d)
+		shifted[i] = st
+	}
+	return shifted
+}
+
+func sortStopTimes(stopTimes []StopTime) {
+	slices.SortFunc(stopTimes, func(a, b StopTime) int {
+		return cmp.Compare(a.StopSequence, b.StopSequence)
+	})
+}
+
+func sortFrequencies(frequencies []Frequency) {
+	slices.SortFunc(frequencies, func(a, b Frequency) int {
+		return a.StartTime.Compare(b.StartTime.Time)
+	})
+}
+
+// validateFrequencies checks that frequencies refer to trips, and that the
+// frequencies of a trip do not overlap.
+func (s *GTFSSchedule) validateFrequencies() {
+	byTrip := make(map[string][]Frequency)
+	for _, f := range s.Frequencies {
+		byTrip[f.TripID] = append(byTrip[f.TripID], f)
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(byTrip)) {
+		if _, ok := s.Trips[id]; !ok {
+			s.errors.add(fmt.Errorf("frequencies.txt: unknown trip ID: %s", id))
+			continue
+		}
+
+		frequencies := byTrip[id]
+		sortFrequencies(frequencies)
+		for i := 1; i < len(frequencies); i++ {
+			if frequencies[i].StartTime.Before(frequencies[i-1].EndTime.Time) {
+				start, _ := frequencies[i].StartTime.MarshalText()
+				s.errors.add(fmt.Errorf("frequencies.txt: trip %s: frequency starting at %s overlaps the one before", id, start))
+			}
+		}
+	}
+}
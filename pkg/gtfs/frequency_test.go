@@ -0,0 +1,238 @@
+package gtfs
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clockTime returns the Time of a GTFS time value, such as 25:10:00.
+func clockTime(s string) Time {
+	var t Time
+	if err := t.UnmarshalText([]byte(s)); err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseFrequencies(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	b := bytes.NewBufferString("trip_id,start_time,end_time,headway_secs,exact_times\n" +
+		"T1,06:00:00,09:00:00,300,1\n" +
+		"T1,09:00:00,25:30:00,600,\n" +
+		"T2,09:00:00,06:00:00,600,0\n" +
+		"T3,06:00:00,09:00:00,0,2\n")
+
+	records := map[string]Frequency{}
+	var errs, warnings errorList
+	parse(b, records, &errs, &warnings)
+
+	assert.Equal([]string{
+		"invalid record: end time must be after start time",
+		"invalid record: headway must be greater than 0",
+		"invalid record: invalid exact times: 2",
+	}, errorStrings(errs))
+	assert.Equal(map[string]Frequency{
+		"T1-06:00:00": {TripID: "T1", StartTime: clockTime("06:00:00"), EndTime: clockTime("09:00:00"), HeadwaySecs: 300, ExactTimes: ScheduleBased},
+		"T1-09:00:00": {TripID: "T1", StartTime: clockTime("09:00:00"), EndTime: clockTime("25:30:00"), HeadwaySecs: 600},
+	}, records)
+}
+
+func TestExpandTrip(t *testing.T) {
+	t.Parallel()
+
+	stopTimes := map[string]StopTime{
+		"T1-1": {TripID: "T1", StopID: "S1", StopSequence: 1, ArrivalTime: clockTime("08:00:00"), DepartureTime: clockTime("08:00:30")},
+		"T1-2": {TripID: "T1", StopID: "S2", StopSequence: 2},
+		"T1-3": {TripID: "T1", StopID: "S3", StopSequence: 3, ArrivalTime: clockTime("08:10:00"), DepartureTime: clockTime("08:10:00")},
+	}
+
+	testCases := map[string]struct {
+		frequencies []Frequency
+		starts      []string
+		exact       bool
+	}{
+		"no frequencies": {
+			starts: []string{"08:00:30"},
+			exact:  true,
+		},
+		"exact times": {
+			frequencies: []Frequency{
+				{TripID: "T1", StartTime: clockTime("06:00:00"), EndTime: clockTime("06:20:00"), HeadwaySecs: 600, ExactTimes: ScheduleBased},
+			},
+			starts: []string{"06:00:00", "06:10:00"},
+			exact:  true,
+		},
+		"headways": {
+			frequencies: []Frequency{
+				{TripID: "T1", StartTime: clockTime("23:50:00"), EndTime: clockTime("24:30:00"), HeadwaySecs: 900},
+				{TripID: "T1", StartTime: clockTime("06:00:00"), EndTime: clockTime("06:25:00"), HeadwaySecs: 600},
+			},
+			starts: []string{"06:00:00", "06:10:00", "06:20:00", "23:50:00", "24:05:00", "24:20:00"},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			s := GTFSSchedule{
+				Trips:       map[string]Trip{"T1": {ID: "T1"}},
+				StopTimes:   stopTimes,
+				Frequencies: map[string]Frequency{},
+			}
+			for _, f := range tc.frequencies {
+				s.Frequencies[f.key()] = f
+			}
+
+			instances := s.ExpandTrip(s.Trips["T1"])
+
+			starts := []string{}
+			for _, ti := range instances {
+				start, _ := ti.Start.MarshalText()
+				starts = append(starts, string(start))
+
+				assert.Equal(tc.exact, ti.Exact)
+				assert.Equal(ti.Start, ti.StopTimes[0].DepartureTime)
+				assert.Equal(ti.Start.Add(9*time.Minute+30*time.Second), ti.StopTimes[2].ArrivalTime.Time)
+				assert.True(ti.StopTimes[1].ArrivalTime.IsZero())
+			}
+			assert.Equal(tc.starts, starts)
+		})
+	}
+
+	t.Run("shifted stop times", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		s := GTFSSchedule{
+			Trips:     map[string]Trip{"T1": {ID: "T1"}},
+			StopTimes: stopTimes,
+			Frequencies: map[string]Frequency{
+				"T1-06:00:00": {TripID: "T1", StartTime: clockTime("06:00:00"), EndTime: clockTime("06:01:00"), HeadwaySecs: 600},
+			},
+		}
+
+		instances := s.ExpandTrip(s.Trips["T1"])
+
+		if assert.Len(instances, 1) {
+			ti := instances[0]
+			assert.Equal(-2*time.Hour-30*time.Second, ti.Offset)
+			assert.Equal([]StopTime{
+				{TripID: "T1", StopID: "S1", StopSequence: 1, ArrivalTime: clockTime("05:59:30"), DepartureTime: clockTime("06:00:00")},
+				{TripID: "T1", StopID: "S2", StopSequence: 2},
+				{TripID: "T1", StopID: "S3", StopSequence: 3, ArrivalTime: clockTime("06:09:30"), DepartureTime: clockTime("06:09:30")},
+			}, ti.StopTimes)
+		}
+
+		// the schedule's own stop times are left as they are
+		assert.Equal(clockTime("08:00:30"), s.StopTimes["T1-1"].DepartureTime)
+	})
+
+	t.Run("past midnight", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		s := GTFSSchedule{
+			Trips:     map[string]Trip{"T1": {ID: "T1"}},
+			StopTimes: stopTimes,
+			Frequencies: map[string]Frequency{
+				"T1-23:55:00": {TripID: "T1", StartTime: clockTime("23:55:00"), EndTime: clockTime("24:10:00"), HeadwaySecs: 600},
+				"T1-47:55:00": {TripID: "T1", StartTime: clockTime("47:55:00"), EndTime: clockTime("47:59:00"), HeadwaySecs: 600},
+			},
+		}
+
+		times := []string{}
+		for _, ti := range s.ExpandTrip(s.Trips["T1"]) {
+			for _, st := range []Time{ti.StopTimes[0].ArrivalTime, ti.StopTimes[2].ArrivalTime} {
+				text, err := st.MarshalText()
+				assert.NoError(err)
+				times = append(times, string(text))
+			}
+		}
+		assert.Equal([]string{"23:54:30", "24:04:30", "24:04:30", "24:14:30", "47:54:30", "48:04:30"}, times)
+	})
+
+	t.Run("no stop times", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		s := GTFSSchedule{Trips: map[string]Trip{"T2": {ID: "T2"}}, StopTimes: stopTimes}
+
+		assert.Nil(s.ExpandTrip(s.Trips["T2"]))
+	})
+
+	t.Run("untimed first stop", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		s := GTFSSchedule{
+			Trips: map[string]Trip{"T2": {ID: "T2"}},
+			StopTimes: map[string]StopTime{
+				"T2-1": {TripID: "T2", StopID: "S1", StopSequence: 1},
+				"T2-2": {TripID: "T2", StopID: "S2", StopSequence: 2, ArrivalTime: clockTime("08:10:00"), DepartureTime: clockTime("08:10:00")},
+			},
+			Frequencies: map[string]Frequency{
+				"T2-06:00:00": {TripID: "T2", StartTime: clockTime("06:00:00"), EndTime: clockTime("07:00:00"), HeadwaySecs: 1800},
+			},
+		}
+
+		assert.Nil(s.ExpandTrip(s.Trips["T2"]))
+	})
+}
+
+func TestTripInstances(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s := GTFSSchedule{
+		Trips: map[string]Trip{"T1": {ID: "T1"}, "T2": {ID: "T2"}, "T3": {ID: "T3"}},
+		StopTimes: map[string]StopTime{
+			"T1-1": {TripID: "T1", StopSequence: 1, DepartureTime: clockTime("07:00:00")},
+			"T2-1": {TripID: "T2", StopSequence: 1, DepartureTime: clockTime("08:00:00")},
+		},
+		Frequencies: map[string]Frequency{
+			"T2-06:00:00": {TripID: "T2", StartTime: clockTime("06:00:00"), EndTime: clockTime("07:00:00"), HeadwaySecs: 1800},
+		},
+	}
+
+	var runs []string
+	for ti := range s.TripInstances() {
+		start, _ := ti.Start.MarshalText()
+		runs = append(runs, ti.Trip.ID+" "+string(start))
+	}
+
+	assert.Equal([]string{"T1 07:00:00", "T2 06:00:00", "T2 06:30:00"}, runs)
+}
+
+func TestValidateFrequencies(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s := GTFSSchedule{
+		Trips: map[string]Trip{"T1": {ID: "T1"}},
+		Frequencies: map[string]Frequency{
+			"T1-06:00:00": {TripID: "T1", StartTime: clockTime("06:00:00"), EndTime: clockTime("09:00:00"), HeadwaySecs: 300},
+			"T1-08:00:00": {TripID: "T1", StartTime: clockTime("08:00:00"), EndTime: clockTime("10:00:00"), HeadwaySecs: 600},
+			"T1-10:00:00": {TripID: "T1", StartTime: clockTime("10:00:00"), EndTime: clockTime("12:00:00"), HeadwaySecs: 600},
+			"T9-06:00:00": {TripID: "T9", StartTime: clockTime("06:00:00"), EndTime: clockTime("09:00:00"), HeadwaySecs: 300},
+		},
+	}
+
+	s.validate()
+
+	assert.Equal([]string{
+		"frequencies.txt: trip T1: frequency starting at 08:00:00 overlaps the one before",
+		"frequencies.txt: unknown trip ID: T9",
+	}, errorStrings(s.errors))
+	assert.True(slices.IsSortedFunc(s.TripFrequencies(s.Trips["T1"]), func(a, b Frequency) int {
+		return a.StartTime.Compare(b.StartTime.Time)
+	}))
+}
@@ -6,8 +6,8 @@ import (
 )
 
 type Level struct {
-	ID    string  `json:"levelId" csv:"level_id"`
-	Index float64 `json:"levelIndex" csv:"level_index"`
+	ID    string  `json:"levelId" csv:"level_id,required"`
+	Index float64 `json:"levelIndex" csv:"level_index,required"`
 	Name  string  `json:"levelName,omitempty" csv:"level_name"`
 }
 
@@ -18,12 +18,13 @@ func (l Level) key() string {
 func (l Level) validate() errorList {
 	var errs errorList
 
-	if l.ID == "" {
-		errs.add(fmt.Errorf("missing level_id"))
-	}
 	if l.Index == math.Inf(-1) {
 		errs.add(fmt.Errorf("invalid index valie"))
 	}
 
 	return errs
 }
+
+func (l Level) AfterUnmarshalCSV() error {
+	return l.validate().err()
+}
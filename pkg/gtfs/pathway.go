@@ -0,0 +1,350 @@
+package gtfs
+
+import (
+	"container/heap"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+type Pathway struct {
+	ID                   string   `json:"pathwayId" csv:"pathway_id,required"`
+	FromStopID           string   `json:"fromStopId" csv:"from_stop_id,required"`
+	ToStopID             string   `json:"toStopId" csv:"to_stop_id,required"`
+	Mode                 int      `json:"pathwayMode" csv:"pathway_mode,required"`
+	IsBidirectional      int      `json:"isBidirectional" csv:"is_bidirectional,required"`
+	Length               *float64 `json:"length,omitempty" csv:"length"`
+	TraversalTime        *int     `json:"traversalTime,omitempty" csv:"traversal_time"`
+	StairCount           *int     `json:"stairCount,omitempty" csv:"stair_count"`
+	MaxSlope             *float64 `json:"maxSlope,omitempty" csv:"max_slope"`
+	MinWidth             *float64 `json:"minWidth,omitempty" csv:"min_width"`
+	SignpostedAs         string   `json:"signpostedAs,omitempty" csv:"signposted_as"`
+	ReversedSignpostedAs string   `json:"reversedSignpostedAs,omitempty" csv:"reversed_signposted_as"`
+}
+
+func (p Pathway) key() string {
+	return p.ID
+}
+
+func (p Pathway) validate() errorList {
+	var errs errorList
+
+	if p.Mode < PathwayMode.L || p.Mode > PathwayMode.U {
+		errs.add(fmt.Errorf("invalid pathway mode: %d", p.Mode))
+	}
+	if p.IsBidirectional < IsBidirectional.L || p.IsBidirectional > IsBidirectional.U {
+		errs.add(fmt.Errorf("invalid bidirectional value: %d", p.IsBidirectional))
+	}
+	if p.Mode == ExitGate && p.IsBidirectional == Bidirectional {
+		errs.add(fmt.Errorf("exit gates must not be bidirectional"))
+	}
+	if p.Length != nil && *p.Length < 0 {
+		errs.add(fmt.Errorf("length must be greater than or equal to 0"))
+	}
+	if p.TraversalTime != nil && *p.TraversalTime <= 0 {
+		errs.add(fmt.Errorf("traversal time must be greater than 0"))
+	}
+	if p.StairCount != nil && *p.StairCount == 0 {
+		errs.add(fmt.Errorf("stair count must not be 0"))
+	}
+	if p.MinWidth != nil && *p.MinWidth <= 0 {
+		errs.add(fmt.Errorf("min width must be greater than 0"))
+	}
+
+	return errs
+}
+
+func (p Pathway) AfterUnmarshalCSV() error {
+	return p.validate().err()
+}
+
+// walkingSpeed, in meters per second, estimates the traversal time of
+// pathways with a length but no traversal time.
+const walkingSpeed = 1.2
+
+// defaultTraversalTime is the traversal time of pathways with neither a
+// traversal time nor a length, unless PathOptions sets another.
+const defaultTraversalTime = 30 * time.Second
+
+// traversalTime returns the time to walk p: its traversal time, an estimate
+// from its length, or else def.
+func (p Pathway) traversalTime(def time.Duration) time.Duration {
+	if p.TraversalTime != nil && *p.TraversalTime > 0 {
+		return time.Duration(*p.TraversalTime) * time.Second
+	}
+	if p.Length != nil && *p.Length > 0 {
+		return time.Duration(math.Round(*p.Length/walkingSpeed)) * time.Second
+	}
+	return def
+}
+
+// StationGraph is the network of pathways between the locations within a
+// station: its platforms, entrances, generic nodes and boarding areas.
+type StationGraph struct {
+	Station Stop
+	stops   map[string]Stop
+	levels  map[string]Level
+	edges   map[string][]PathStep
+}
+
+// PathStep is a pathway walked in one direction.
+type PathStep struct {
+	Pathway Pathway `json:"pathway"`
+	From    Stop    `json:"from"`
+	To      Stop    `json:"to"`
+	// Reversed reports whether the pathway is walked from its to stop to its
+	// from stop.
+	Reversed bool `json:"reversed"`
+	// FromLevel and ToLevel are the levels of From and To, if they have one.
+	FromLevel Level         `json:"fromLevel"`
+	ToLevel   Level         `json:"toLevel"`
+	Time      time.Duration `json:"time"`
+}
+
+// changesLevel reports whether the step goes between levels with different
+// indexes. A step from or to a location without a level does not.
+func (s PathStep) changesLevel() bool {
+	return s.FromLevel.ID != "" && s.ToLevel.ID != "" && s.FromLevel.Index != s.ToLevel.Index
+}
+
+// Path is a route through a station.
+type Path struct {
+	Steps []PathStep `json:"steps"`
+	// Levels are the levels the path passes through, in order, starting
+	// with the level of its first location. Locations without a level are
+	// left out.
+	Levels []Level       `json:"levels"`
+	Time   time.Duration `json:"time"`
+}
+
+// PathOptions restricts the pathways a path may take, and how they are timed.
+type PathOptions struct {
+	// AvoidStairs leaves out stairs, for riders using wheelchairs or with
+	// luggage. Levels are then only changed by elevators, escalators unless
+	// they are avoided too, and ramps, which are walkways and moving
+	// sidewalks with a max slope. Other pathways between levels may have
+	// steps.
+	AvoidStairs bool
+	// AvoidEscalators leaves out escalators, which riders using wheelchairs
+	// cannot use either.
+	AvoidEscalators bool
+	// DefaultTraversalTime is the time to walk pathways with neither a
+	// traversal time nor a length. If zero, it is 30 seconds.
+	DefaultTraversalTime time.Duration
+}
+
+func (o PathOptions) allows(step PathStep) bool {
+	p := step.Pathway
+	if o.AvoidStairs && p.Mode == Stairs || o.AvoidEscalators && p.Mode == Escalator {
+		return false
+	}
+	if o.AvoidStairs && step.changesLevel() {
+		switch p.Mode {
+		case Elevator, Escalator:
+			return true
+		case Walkway, MovingSidewalk:
+			return p.MaxSlope != nil && *p.MaxSlope > 0
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// traversalTime returns the time to walk p with the options.
+func (o PathOptions) traversalTime(p Pathway) time.Duration {
+	if o.DefaultTraversalTime > 0 {
+		return p.traversalTime(o.DefaultTraversalTime)
+	}
+	return p.traversalTime(defaultTraversalTime)
+}
+
+// StationGraph returns the graph of the pathways within the station with ID
+// stationID.
+func (s GTFSSchedule) StationGraph(stationID string) (*StationGraph, error) {
+	station, ok := s.Stops[stationID]
+	if !ok {
+		return nil, fmt.Errorf("unknown station: %s", stationID)
+	}
+	if station.LocationType != Station {
+		return nil, fmt.Errorf("stop %s is not a station", stationID)
+	}
+
+	g := &StationGraph{
+		Station: station,
+		stops:   make(map[string]Stop),
+		levels:  s.Levels,
+		edges:   make(map[string][]PathStep),
+	}
+
+	for id, stop := range s.Stops {
+		if id != stationID && slices.Contains(stopAncestors(s.Stops, id), stationID) {
+			g.stops[id] = stop
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(s.Pathways)) {
+		p := s.Pathways[id]
+		from, ok := g.stops[p.FromStopID]
+		if !ok {
+			continue
+		}
+		to, ok := g.stops[p.ToStopID]
+		if !ok {
+			continue
+		}
+
+		g.addStep(PathStep{Pathway: p, From: from, To: to})
+		if p.IsBidirectional == Bidirectional {
+			g.addStep(PathStep{Pathway: p, From: to, To: from, Reversed: true})
+		}
+	}
+
+	return g, nil
+}
+
+func (g *StationGraph) addStep(step PathStep) {
+	step.FromLevel = g.levels[step.From.LevelID]
+	step.ToLevel = g.levels[step.To.LevelID]
+	g.edges[step.From.ID] = append(g.edges[step.From.ID], step)
+}
+
+// Entrances returns the entrances and exits of the station, in ID order.
+func (g *StationGraph) Entrances() []Stop {
+	return g.locations(EntranceExit)
+}
+
+// Platforms returns the platforms of the station, in ID order.
+func (g *StationGraph) Platforms() []Stop {
+	return g.locations(StopPlatform)
+}
+
+func (g *StationGraph) locations(locationType int) []Stop {
+	var stops []Stop
+	for _, id := range slices.Sorted(maps.Keys(g.stops)) {
+		if g.stops[id].LocationType == locationType {
+			stops = append(stops, g.stops[id])
+		}
+	}
+	return stops
+}
+
+// ShortestPath returns the quickest path from the location with ID from to
+// the location with ID to, by traversal time, taking only the pathways opts
+// allows, and changing levels only as it allows. It reports false if there is
+// no such path.
+func (g *StationGraph) ShortestPath(from, to string, opts PathOptions) (Path, bool) {
+	if _, ok := g.stops[from]; !ok {
+		return Path{}, false
+	}
+	if _, ok := g.stops[to]; !ok {
+		return Path{}, false
+	}
+
+	times := map[string]time.Duration{from: 0}
+	prev := make(map[string]PathStep)
+	visited := make(map[string]bool)
+
+	queue := &pathQueue{{stop: from}}
+	for queue.Len() > 0 {
+		n := heap.Pop(queue).(pathNode)
+		if visited[n.stop] {
+			continue
+		}
+		visited[n.stop] = true
+
+		if n.stop == to {
+			break
+		}
+
+		for _, step := range g.edges[n.stop] {
+			if !opts.allows(step) {
+				continue
+			}
+
+			step.Time = opts.traversalTime(step.Pathway)
+			t := n.time + step.Time
+			if best, ok := times[step.To.ID]; ok && t >= best {
+				continue
+			}
+			times[step.To.ID] = t
+			prev[step.To.ID] = step
+			heap.Push(queue, pathNode{stop: step.To.ID, time: t})
+		}
+	}
+
+	if !visited[to] {
+		return Path{}, false
+	}
+
+	var steps []PathStep
+	for id := to; id != from; {
+		step := prev[id]
+		steps = append(steps, step)
+		id = step.From.ID
+	}
+	slices.Reverse(steps)
+
+	return Path{Steps: steps, Levels: g.pathLevels(from, steps), Time: times[to]}, true
+}
+
+// pathLevels returns the levels passed through by steps from the location
+// with ID from.
+func (g *StationGraph) pathLevels(from string, steps []PathStep) []Level {
+	var levels []Level
+	add := func(l Level) {
+		if l.ID != "" && (len(levels) == 0 || levels[len(levels)-1].ID != l.ID) {
+			levels = append(levels, l)
+		}
+	}
+
+	add(g.levels[g.stops[from].LevelID])
+	for _, step := range steps {
+		add(step.ToLevel)
+	}
+	return levels
+}
+
+type pathNode struct {
+	stop string
+	time time.Duration
+}
+
+// pathQueue is a priority queue of the locations to visit, quickest first.
+type pathQueue []pathNode
+
+func (q pathQueue) Len() int           { return len(q) }
+func (q pathQueue) Less(i, j int) bool { return q[i].time < q[j].time }
+func (q pathQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *pathQueue) Push(x any) {
+	*q = append(*q, x.(pathNode))
+}
+
+func (q *pathQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// validatePathways checks that pathways join locations that exist and are not
+// stations.
+func (s *GTFSSchedule) validatePathways() {
+	for _, id := range slices.Sorted(maps.Keys(s.Pathways)) {
+		p := s.Pathways[id]
+
+		for _, stopID := range []string{p.FromStopID, p.ToStopID} {
+			stop, ok := s.Stops[stopID]
+			if !ok {
+				s.errors.add(fmt.Errorf("pathways.txt: pathway %s: unknown stop ID: %s", id, stopID))
+				continue
+			}
+			if stop.LocationType == Station {
+				s.errors.add(fmt.Errorf("pathways.txt: pathway %s: stop %s is a station", id, stopID))
+			}
+		}
+	}
+}
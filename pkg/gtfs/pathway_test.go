@@ -0,0 +1,222 @@
+package gtfs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePathways(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	b := bytes.NewBufferString("pathway_id,from_stop_id,to_stop_id,pathway_mode,is_bidirectional,length,traversal_time,stair_count,min_width\n" +
+		"PW1,E1,N1,1,1,25.5,20,,1.5\n" +
+		"PW2,N1,P1,8,2,,,,\n" +
+		"PW3,P1,E1,7,1,,,,\n" +
+		"PW4,N1,P1,2,1,-1,0,0,0\n")
+
+	records := map[string]Pathway{}
+	var errs, warnings errorList
+	parse(b, records, &errs, &warnings)
+
+	length, traversal, width := 25.5, 20, 1.5
+	assert.Equal([]string{
+		"invalid record: invalid pathway mode: 8",
+		"invalid record: invalid bidirectional value: 2",
+		"invalid record: exit gates must not be bidirectional",
+		"invalid record: length must be greater than or equal to 0",
+		"invalid record: traversal time must be greater than 0",
+		"invalid record: stair count must not be 0",
+		"invalid record: min width must be greater than 0",
+	}, errorStrings(errs))
+	assert.Equal(map[string]Pathway{
+		"PW1": {ID: "PW1", FromStopID: "E1", ToStopID: "N1", Mode: Walkway, IsBidirectional: Bidirectional, Length: &length, TraversalTime: &traversal, MinWidth: &width},
+	}, records)
+}
+
+func TestStationGraph(t *testing.T) {
+	t.Parallel()
+
+	seconds := func(n int) *int { return &n }
+	meters := func(n float64) *float64 { return &n }
+	slope := func(n float64) *float64 { return &n }
+
+	s := GTFSSchedule{
+		Stops: map[string]Stop{
+			"ST1": {ID: "ST1", LocationType: Station},
+			"E1":  {ID: "E1", LocationType: EntranceExit, ParentStation: "ST1", LevelID: "L0"},
+			"E2":  {ID: "E2", LocationType: EntranceExit, ParentStation: "ST1", LevelID: "L0"},
+			"N1":  {ID: "N1", LocationType: GenericNode, ParentStation: "ST1", LevelID: "L0"},
+			"N2":  {ID: "N2", LocationType: GenericNode, ParentStation: "ST1", LevelID: "L-1"},
+			"P1":  {ID: "P1", LocationType: StopPlatform, ParentStation: "ST1", LevelID: "L-2"},
+			"P2":  {ID: "P2", LocationType: StopPlatform, ParentStation: "ST1", LevelID: "L-2"},
+			"BA1": {ID: "BA1", LocationType: BoardingArea, ParentStation: "P1"},
+			"ST2": {ID: "ST2", LocationType: Station},
+			"P9":  {ID: "P9", LocationType: StopPlatform, ParentStation: "ST2"},
+		},
+		Levels: map[string]Level{
+			"L0":  {ID: "L0", Index: 0, Name: "Street"},
+			"L-1": {ID: "L-1", Index: -1, Name: "Concourse"},
+			"L-2": {ID: "L-2", Index: -2, Name: "Platforms"},
+		},
+		Pathways: map[string]Pathway{
+			// E1 reaches the concourse by stairs or a longer walk past the elevator
+			"PW1": {ID: "PW1", FromStopID: "E1", ToStopID: "N2", Mode: Stairs, IsBidirectional: Bidirectional, TraversalTime: seconds(40)},
+			"PW2": {ID: "PW2", FromStopID: "E1", ToStopID: "N1", Mode: Walkway, IsBidirectional: Bidirectional, Length: meters(60)},
+			"PW3": {ID: "PW3", FromStopID: "N1", ToStopID: "N2", Mode: Elevator, IsBidirectional: Bidirectional, TraversalTime: seconds(45)},
+			// or a quicker passage down that is not a ramp, so may have steps
+			"PW10": {ID: "PW10", FromStopID: "N1", ToStopID: "N2", Mode: Walkway, IsBidirectional: Bidirectional, TraversalTime: seconds(20)},
+			// down to the platforms by escalator, or stairs, or a ramp
+			"PW4": {ID: "PW4", FromStopID: "N2", ToStopID: "P1", Mode: Escalator, IsBidirectional: Unidirectional, TraversalTime: seconds(30)},
+			"PW5": {ID: "PW5", FromStopID: "N2", ToStopID: "P1", Mode: Stairs, IsBidirectional: Bidirectional, TraversalTime: seconds(35)},
+			"PW6": {ID: "PW6", FromStopID: "N2", ToStopID: "P1", Mode: Walkway, IsBidirectional: Bidirectional, TraversalTime: seconds(120), MaxSlope: slope(0.08)},
+			"PW7": {ID: "PW7", FromStopID: "P1", ToStopID: "BA1", Mode: Walkway, IsBidirectional: Bidirectional},
+			// one way out through a gate
+			"PW8": {ID: "PW8", FromStopID: "N2", ToStopID: "E2", Mode: ExitGate, IsBidirectional: Unidirectional, TraversalTime: seconds(10)},
+			// pathways leaving the station are not part of its graph
+			"PW9": {ID: "PW9", FromStopID: "P1", ToStopID: "P9", Mode: Walkway, IsBidirectional: Bidirectional},
+		},
+	}
+
+	g, err := s.StationGraph("ST1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("locations", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		assert.Equal([]Stop{s.Stops["E1"], s.Stops["E2"]}, g.Entrances())
+		assert.Equal([]Stop{s.Stops["P1"], s.Stops["P2"]}, g.Platforms())
+	})
+
+	testCases := map[string]struct {
+		from, to string
+		opts     PathOptions
+		pathways []string
+		time     time.Duration
+		ok       bool
+	}{
+		"quickest": {
+			from: "E1", to: "P1",
+			pathways: []string{"PW1", "PW4"}, time: 70 * time.Second, ok: true,
+		},
+		"avoid escalators": {
+			from: "E1", to: "P1", opts: PathOptions{AvoidEscalators: true},
+			pathways: []string{"PW1", "PW5"}, time: 75 * time.Second, ok: true,
+		},
+		"step free": {
+			from: "E1", to: "BA1", opts: PathOptions{AvoidStairs: true, AvoidEscalators: true},
+			pathways: []string{"PW2", "PW3", "PW6", "PW7"}, time: 50*time.Second + 45*time.Second + 120*time.Second + defaultTraversalTime, ok: true,
+		},
+		"default traversal time": {
+			from: "E1", to: "BA1", opts: PathOptions{AvoidStairs: true, AvoidEscalators: true, DefaultTraversalTime: 10 * time.Second},
+			pathways: []string{"PW2", "PW3", "PW6", "PW7"}, time: 50*time.Second + 45*time.Second + 120*time.Second + 10*time.Second, ok: true,
+		},
+		"level change without steps": {
+			from: "E1", to: "N2", opts: PathOptions{AvoidStairs: true},
+			pathways: []string{"PW2", "PW3"}, time: 95 * time.Second, ok: true,
+		},
+		"level change with steps": {
+			from: "E1", to: "N2", opts: PathOptions{AvoidEscalators: true},
+			pathways: []string{"PW1"}, time: 40 * time.Second, ok: true,
+		},
+		"reverse": {
+			from: "P1", to: "E1",
+			pathways: []string{"PW5", "PW1"}, time: 75 * time.Second, ok: true,
+		},
+		"one way": {
+			from: "E2", to: "P1",
+		},
+		"unreachable platform": {
+			from: "E1", to: "P2",
+		},
+		"outside the station": {
+			from: "P1", to: "P9",
+		},
+		"same location": {
+			from: "P1", to: "P1",
+			pathways: []string{}, ok: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			path, ok := g.ShortestPath(tc.from, tc.to, tc.opts)
+
+			assert.Equal(tc.ok, ok)
+			if !tc.ok {
+				return
+			}
+
+			pathways := []string{}
+			for _, step := range path.Steps {
+				pathways = append(pathways, step.Pathway.ID)
+			}
+			assert.Equal(tc.pathways, pathways)
+			assert.Equal(tc.time, path.Time)
+		})
+	}
+
+	t.Run("levels", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		path, _ := g.ShortestPath("P1", "E1", PathOptions{})
+
+		if assert.Len(path.Steps, 2) {
+			assert.True(path.Steps[0].Reversed)
+			assert.Equal("P1", path.Steps[0].From.ID)
+			assert.Equal("Platforms", path.Steps[0].FromLevel.Name)
+			assert.Equal("Concourse", path.Steps[0].ToLevel.Name)
+			assert.Equal("Street", path.Steps[1].ToLevel.Name)
+		}
+		assert.Equal([]Level{s.Levels["L-2"], s.Levels["L-1"], s.Levels["L0"]}, path.Levels)
+
+		// the boarding area has no level of its own
+		path, _ = g.ShortestPath("E1", "BA1", PathOptions{AvoidStairs: true})
+		assert.Equal([]Level{s.Levels["L0"], s.Levels["L-1"], s.Levels["L-2"]}, path.Levels)
+	})
+
+	t.Run("not a station", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		_, err := s.StationGraph("P1")
+		assert.EqualError(err, "stop P1 is not a station")
+
+		_, err = s.StationGraph("ST9")
+		assert.EqualError(err, "unknown station: ST9")
+	})
+}
+
+func TestValidatePathways(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s := GTFSSchedule{
+		Stops: map[string]Stop{
+			"ST1": {ID: "ST1", LocationType: Station},
+			"E1":  {ID: "E1", LocationType: EntranceExit, ParentStation: "ST1"},
+		},
+		Pathways: map[string]Pathway{
+			"PW1": {ID: "PW1", FromStopID: "E1", ToStopID: "ST1", Mode: Walkway},
+			"PW2": {ID: "PW2", FromStopID: "E9", ToStopID: "E1", Mode: Walkway},
+		},
+	}
+
+	s.validate()
+
+	assert.Equal([]string{
+		"pathways.txt: pathway PW1: stop ST1 is a station",
+		"pathways.txt: pathway PW2: unknown stop ID: E9",
+	}, errorStrings(s.errors))
+}
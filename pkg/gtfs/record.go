@@ -1,46 +1,62 @@
 package gtfs
 
+//go:generate go run ../../tools/csvmumgen
+
 import (
+	"errors"
 	"fmt"
 	"io"
 
 	"github.com/bridgelightcloud/bogie/pkg/csvmum"
 )
 
+func init() {
+	csvmum.RegisterParser("color", ParseColor, nil)
+	csvmum.RegisterParser("currency", ParseCurrencyCode, nil)
+}
+
+// record is a row of a GTFS file. Records validate themselves as they are
+// unmarshaled, in AfterUnmarshalCSV.
 type record interface {
 	key() string
-	validate() errorList
+	csvmum.AfterUnmarshaler
 }
 
-func parse[T record](f io.Reader, records map[string]T, errors *errorList) {
-	csvm, err := csvmum.NewUnmarshaler[T](f)
+func parse[T record](f io.Reader, records map[string]T, errs *errorList, warnings *errorList) {
+	csvm, err := csvmum.NewUnmarshaler[T](f,
+		csvmum.WithHeaderPolicy(csvmum.HeaderWarn),
+		csvmum.ContinueOnError(),
+		csvmum.StripBOM(),
+		csvmum.TrimSpace(),
+	)
 	if err != nil {
-		errors.add(fmt.Errorf("error creating unmarshaler for file: %w", err))
+		errs.add(fmt.Errorf("error creating unmarshaler for file: %w", err))
 		return
 	}
 
-	for {
-		var r T
-
-		err = csvm.Unmarshal(&r)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			errors.add(fmt.Errorf("error unmarshalling file: %w", err))
+	for _, d := range csvm.Diagnostics() {
+		// optional columns are routinely left out of feeds
+		if d.Kind == csvmum.MissingColumn {
 			continue
 		}
+		warnings.add(fmt.Errorf("invalid header: %s", d))
+	}
 
-		errs := r.validate()
-		if errs != nil {
-			for _, e := range errs {
-				errors.add(fmt.Errorf("invalid record: %w", e))
+	for r, err := range csvm.All() {
+		var invalid errorList
+		if errors.As(err, &invalid) {
+			for _, e := range invalid {
+				errs.add(fmt.Errorf("invalid record: %w", e))
 			}
 			continue
 		}
+		if err != nil {
+			errs.add(fmt.Errorf("error unmarshalling file: %w", err))
+			continue
+		}
 
 		if _, ok := records[r.key()]; ok {
-			errors.add(fmt.Errorf("duplicate key: %s", r.key()))
+			errs.add(fmt.Errorf("duplicate key: %s", r.key()))
 			continue
 		}
 
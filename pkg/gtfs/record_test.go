@@ -0,0 +1,153 @@
+package gtfs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bridgelightcloud/bogie/pkg/csvmum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("level_id,level_index,level_name\nL1,0,Ground\nL2,-1.5,Mezzanine\n")
+
+		records := map[string]Level{}
+		var errs, warnings errorList
+		parse(b, records, &errs, &warnings)
+
+		assert.Empty(errs)
+		assert.Empty(warnings)
+		assert.Equal(map[string]Level{
+			"L1": {ID: "L1", Index: 0, Name: "Ground"},
+			"L2": {ID: "L2", Index: -1.5, Name: "Mezzanine"},
+		}, records)
+	})
+
+	t.Run("bom and padding", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("\ufeffagency_id,agency_name,agency_url,agency_timezone\n A1 , Metro ,https://metro.example,America/Los_Angeles\n")
+
+		records := map[string]Agency{}
+		var errs, warnings errorList
+		parse(b, records, &errs, &warnings)
+
+		assert.Empty(errs)
+		assert.Empty(warnings)
+		assert.Equal(map[string]Agency{
+			"A1": {ID: "A1", Name: "Metro", URL: "https://metro.example", Timezone: "America/Los_Angeles"},
+		}, records)
+	})
+
+	t.Run("header warnings", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("level_id,level_index,Level_Name,levle_color\nL1,0,Ground,red\n")
+
+		records := map[string]Level{}
+		var errs, warnings errorList
+		parse(b, records, &errs, &warnings)
+
+		assert.Empty(errs)
+		assert.Equal([]string{
+			`invalid header: mismatched column 3: "Level_Name" should be "level_name"`,
+			`invalid header: unknown column 4: "levle_color"`,
+		}, errorStrings(warnings))
+		// the mismatched column is not decoded
+		assert.Equal(map[string]Level{"L1": {ID: "L1", Index: 0}}, records)
+	})
+
+	t.Run("missing required column", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("level_id,level_name\nL1,Ground\n")
+
+		records := map[string]Level{}
+		var errs, warnings errorList
+		parse(b, records, &errs, &warnings)
+
+		assert.Equal([]string{
+			"error creating unmarshaler for file: cannot unmarshal: missing required column: level_index",
+		}, errorStrings(errs))
+		assert.Empty(records)
+	})
+
+	t.Run("route colors", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("route_id,route_short_name,route_long_name,route_type,route_color,route_text_color\n" +
+			"R1,1,Main Street,3, 00ff7f ,\n" +
+			"R2,2,Broadway,3,green,FFFFFF\n")
+
+		records := map[string]Route{}
+		var errs, warnings errorList
+		parse(b, records, &errs, &warnings)
+
+		assert.Equal([]string{
+			`error unmarshalling file: cannot unmarshal line 3, column "route_color", field Color, value "green": invalid color: green`,
+		}, errorStrings(errs))
+		assert.Equal(map[string]Route{
+			"R1": {ID: "R1", ShortName: "1", LongName: "Main Street", Type: "3", Color: "00FF7F"},
+		}, records)
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		b := bytes.NewBufferString("level_id,level_index\nL1,0\nL1,1\n")
+
+		records := map[string]Level{}
+		var errs, warnings errorList
+		parse(b, records, &errs, &warnings)
+
+		assert.Equal([]string{"duplicate key: L1"}, errorStrings(errs))
+		assert.Equal(map[string]Level{"L1": {ID: "L1", Index: 0}}, records)
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	b := bytes.NewBufferString("trip_id,stop_sequence,stop_id,timepoint\nT1,1,S1,1\nT1,two,S2,1\nT1,3,S3,x\n")
+
+	records := map[string]StopTime{}
+	var errs, warnings errorList
+	parse(b, records, &errs, &warnings)
+
+	assert.Len(records, 1)
+	assert.Len(errs, 2)
+
+	var pe *csvmum.ParseError
+	if assert.ErrorAs(errs[0], &pe) {
+		assert.Equal(3, pe.Line)
+		assert.Equal(2, pe.Column)
+		assert.Equal("stop_sequence", pe.Header)
+		assert.Equal("StopSequence", pe.Field)
+		assert.Equal("two", pe.Value)
+	}
+	if assert.ErrorAs(errs[1], &pe) {
+		assert.Equal(4, pe.Line)
+		assert.Equal("timepoint", pe.Header)
+		assert.Equal("x", pe.Value)
+	}
+}
+
+func errorStrings(errs errorList) []string {
+	ss := make([]string, len(errs))
+	for i, err := range errs {
+		ss[i] = err.Error()
+	}
+	return ss
+}
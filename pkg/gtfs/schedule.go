@@ -3,6 +3,7 @@ package gtfs
 import (
 	"archive/zip"
 	"fmt"
+	"time"
 )
 
 type GTFSSchedule struct {
@@ -16,6 +17,16 @@ type GTFSSchedule struct {
 	StopTimes     map[string]StopTime
 	Levels        map[string]Level
 
+	// Optional files
+	Shapes         map[string]Shape
+	Frequencies    map[string]Frequency
+	Transfers      map[string]Transfer
+	Pathways       map[string]Pathway
+	FeedInfo       *FeedInfo
+	Attributions   map[string]Attribution
+	FareAttributes map[string]FareAttribute
+	FareRules      map[string]FareRule
+
 	unusedFiles []string
 	errors      errorList
 	warnings    errorList
@@ -25,53 +36,107 @@ func (s GTFSSchedule) Errors() errorList {
 	return s.errors
 }
 
+func (s GTFSSchedule) Warnings() errorList {
+	return s.warnings
+}
+
 type gtfsSpec[R record] struct {
 	set func(*GTFSSchedule, map[string]R)
 }
 
 type fileParser interface {
-	parseFile(*zip.File, *GTFSSchedule, *errorList)
+	parseFile(*zip.File, *GTFSSchedule)
 }
 
-func (spec gtfsSpec[R]) parseFile(f *zip.File, schedule *GTFSSchedule, errors *errorList) {
+func (spec gtfsSpec[R]) parseFile(f *zip.File, schedule *GTFSSchedule) {
 	r, err := f.Open()
 	if err != nil {
-		errors.add(fmt.Errorf("error opening file: %w", err))
+		schedule.errors.add(fmt.Errorf("error opening file: %w", err))
 		return
 	}
 	defer r.Close()
 
 	records := make(map[string]R)
 
-	parse(r, records, errors)
+	var errs, warnings errorList
+	parse(r, records, &errs, &warnings)
+
+	for _, err := range errs {
+		schedule.errors.add(fmt.Errorf("%s: %w", f.Name, err))
+	}
+	for _, w := range warnings {
+		schedule.warnings.add(fmt.Errorf("%s: %w", f.Name, w))
+	}
 
 	spec.set(schedule, records)
 }
 
 var gtfsSpecs = map[string]fileParser{
-	"agency.txt":         gtfsSpec[Agency]{set: func(s *GTFSSchedule, r map[string]Agency) { s.Agencies = r }},
-	"stops.txt":          gtfsSpec[Stop]{set: func(s *GTFSSchedule, r map[string]Stop) { s.Stops = r }},
-	"routes.txt":         gtfsSpec[Route]{set: func(s *GTFSSchedule, r map[string]Route) { s.Routes = r }},
-	"calendar.txt":       gtfsSpec[Calendar]{set: func(s *GTFSSchedule, r map[string]Calendar) { s.Calendar = r }},
-	"calendar_dates.txt": gtfsSpec[CalendarDate]{set: func(s *GTFSSchedule, r map[string]CalendarDate) { s.CalendarDates = r }},
-	"trips.txt":          gtfsSpec[Trip]{set: func(s *GTFSSchedule, r map[string]Trip) { s.Trips = r }},
-	"stop_times.txt":     gtfsSpec[StopTime]{set: func(s *GTFSSchedule, r map[string]StopTime) { s.StopTimes = r }},
-	"levels.txt":         gtfsSpec[Level]{set: func(s *GTFSSchedule, r map[string]Level) { s.Levels = r }},
+	"agency.txt":          gtfsSpec[Agency]{set: func(s *GTFSSchedule, r map[string]Agency) { s.Agencies = r }},
+	"stops.txt":           gtfsSpec[Stop]{set: func(s *GTFSSchedule, r map[string]Stop) { s.Stops = r }},
+	"routes.txt":          gtfsSpec[Route]{set: func(s *GTFSSchedule, r map[string]Route) { s.Routes = r }},
+	"calendar.txt":        gtfsSpec[Calendar]{set: func(s *GTFSSchedule, r map[string]Calendar) { s.Calendar = r }},
+	"calendar_dates.txt":  gtfsSpec[CalendarDate]{set: func(s *GTFSSchedule, r map[string]CalendarDate) { s.CalendarDates = r }},
+	"trips.txt":           gtfsSpec[Trip]{set: func(s *GTFSSchedule, r map[string]Trip) { s.Trips = r }},
+	"stop_times.txt":      gtfsSpec[StopTime]{set: func(s *GTFSSchedule, r map[string]StopTime) { s.StopTimes = r }},
+	"levels.txt":          gtfsSpec[Level]{set: func(s *GTFSSchedule, r map[string]Level) { s.Levels = r }},
+	"shapes.txt":          gtfsSpec[ShapePoint]{set: (*GTFSSchedule).setShapes},
+	"frequencies.txt":     gtfsSpec[Frequency]{set: func(s *GTFSSchedule, r map[string]Frequency) { s.Frequencies = r }},
+	"transfers.txt":       gtfsSpec[Transfer]{set: func(s *GTFSSchedule, r map[string]Transfer) { s.Transfers = r }},
+	"pathways.txt":        gtfsSpec[Pathway]{set: func(s *GTFSSchedule, r map[string]Pathway) { s.Pathways = r }},
+	"feed_info.txt":       gtfsSpec[FeedInfo]{set: (*GTFSSchedule).setFeedInfo},
+	"attributions.txt":    gtfsSpec[Attribution]{set: func(s *GTFSSchedule, r map[string]Attribution) { s.Attributions = r }},
+	"fare_attributes.txt": gtfsSpec[FareAttribute]{set: func(s *GTFSSchedule, r map[string]FareAttribute) { s.FareAttributes = r }},
+	"fare_rules.txt":      gtfsSpec[FareRule]{set: func(s *GTFSSchedule, r map[string]FareRule) { s.FareRules = r }},
+}
+
+// defaultExpiryHorizon is how long before the end of its service window a
+// schedule is warned to expire soon.
+const defaultExpiryHorizon = 7 * 24 * time.Hour
+
+type scheduleOptions struct {
+	now           time.Time
+	expiryHorizon time.Duration
+}
+
+type ScheduleOption func(*scheduleOptions)
+
+func buildScheduleOptions(opts []ScheduleOption) scheduleOptions {
+	o := scheduleOptions{now: time.Now(), expiryHorizon: defaultExpiryHorizon}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithExpiryHorizon sets how long before the end of its service window a
+// schedule is warned to expire soon. The default is 7 days.
+func WithExpiryHorizon(d time.Duration) ScheduleOption {
+	return func(o *scheduleOptions) {
+		o.expiryHorizon = d
+	}
 }
 
-func OpenScheduleFromZipFile(fn string) (GTFSSchedule, error) {
+// AsOf checks whether a schedule has expired as of t, rather than now.
+func AsOf(t time.Time) ScheduleOption {
+	return func(o *scheduleOptions) {
+		o.now = t
+	}
+}
+
+func OpenScheduleFromZipFile(fn string, opts ...ScheduleOption) (GTFSSchedule, error) {
 	r, err := zip.OpenReader(fn)
 	if err != nil {
 		return GTFSSchedule{}, err
 	}
 	defer r.Close()
 
-	s := parseSchedule(r)
+	s := parseSchedule(r, buildScheduleOptions(opts))
 
 	return s, nil
 }
 
-func parseSchedule(r *zip.ReadCloser) GTFSSchedule {
+func parseSchedule(r *zip.ReadCloser, o scheduleOptions) GTFSSchedule {
 	var s GTFSSchedule
 
 	for _, f := range r.File {
@@ -81,8 +146,21 @@ func parseSchedule(r *zip.ReadCloser) GTFSSchedule {
 			s.warnings.add(fmt.Errorf("unused file: %s", f.Name))
 			continue
 		}
-		spec.parseFile(f, &s, &s.errors)
+		spec.parseFile(f, &s)
 	}
 
+	s.validate()
+	s.checkExpiry(o.now, o.expiryHorizon)
+
 	return s
 }
+
+// validate checks the references between files, once every file is parsed.
+func (s *GTFSSchedule) validate() {
+	s.validateShapes()
+	s.validateFrequencies()
+	s.validateTransfers()
+	s.validatePathways()
+	s.validateAttributions()
+	s.validateFares()
+}
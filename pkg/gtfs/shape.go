@@ -0,0 +1,123 @@
+package gtfs
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+type ShapePoint struct {
+	ShapeID      string   `json:"shapeId" csv:"shape_id,required"`
+	Coords       Coords   `json:"coords" csv:",prefix=shape_pt_"`
+	Sequence     int      `json:"shapePtSequence" csv:"shape_pt_sequence,required"`
+	DistTraveled *float64 `json:"shapeDistTraveled,omitempty" csv:"shape_dist_traveled"`
+}
+
+func (sp ShapePoint) key() string {
+	return fmt.Sprintf("%s-%d", sp.ShapeID, sp.Sequence)
+}
+
+func (sp ShapePoint) validate() errorList {
+	var errs errorList
+
+	if !sp.Coords.IsValid() {
+		errs.add(fmt.Errorf("invalid shape point coordinates"))
+	}
+	if sp.Sequence < 0 {
+		errs.add(fmt.Errorf("shape point sequence must be greater than or equal to 0"))
+	}
+	if sp.DistTraveled != nil && *sp.DistTraveled < 0 {
+		errs.add(fmt.Errorf("shape distance traveled must be greater than or equal to 0"))
+	}
+
+	return errs
+}
+
+func (sp ShapePoint) AfterUnmarshalCSV() error {
+	return sp.validate().err()
+}
+
+// Shape is the path vehicles travel along a trip, assembled from the points
+// in shapes.txt that share its ID, in sequence order.
+type Shape struct {
+	ID     string       `json:"shapeId"`
+	Points []ShapePoint `json:"points"`
+}
+
+func (s Shape) validate() errorList {
+	var errs errorList
+
+	if len(s.Points) < 2 {
+		errs.add(fmt.Errorf("shape %s has fewer than 2 points", s.ID))
+	}
+
+	// points are sorted by sequence, and a repeated sequence is a duplicate
+	// key of shapes.txt, so only distances can be out of order
+	var last *ShapePoint
+	for i, p := range s.Points {
+		if p.DistTraveled == nil {
+			continue
+		}
+		if last != nil && *p.DistTraveled <= *last.DistTraveled {
+			errs.add(fmt.Errorf("shape %s: distance traveled at sequence %d does not increase", s.ID, p.Sequence))
+		}
+		last = &s.Points[i]
+	}
+
+	return errs
+}
+
+// buildShapes assembles the points of shapes.txt into shapes, returning the
+// problems found with each shape.
+func buildShapes(points map[string]ShapePoint) (map[string]Shape, errorList) {
+	shapes := make(map[string]Shape)
+	for _, p := range points {
+		s := shapes[p.ShapeID]
+		s.ID = p.ShapeID
+		s.Points = append(s.Points, p)
+		shapes[p.ShapeID] = s
+	}
+
+	var errs errorList
+	for _, id := range slices.Sorted(maps.Keys(shapes)) {
+		s := shapes[id]
+		slices.SortFunc(s.Points, func(a, b ShapePoint) int {
+			return cmp.Compare(a.Sequence, b.Sequence)
+		})
+		errs = append(errs, s.validate()...)
+	}
+
+	return shapes, errs
+}
+
+// setShapes assembles the shapes of the schedule from the points of
+// shapes.txt.
+func (s *GTFSSchedule) setShapes(points map[string]ShapePoint) {
+	var errs errorList
+	s.Shapes, errs = buildShapes(points)
+	for _, err := range errs {
+		s.errors.add(fmt.Errorf("shapes.txt: %w", err))
+	}
+}
+
+// TripShape returns the shape of trip t, and whether t has a shape in the
+// schedule.
+func (s GTFSSchedule) TripShape(t Trip) (Shape, bool) {
+	if t.ShapeID == "" {
+		return Shape{}, false
+	}
+
+	shape, ok := s.Shapes[t.ShapeID]
+	return shape, ok
+}
+
+// validateShapes checks that trips refer to shapes.
+func (s *GTFSSchedule) validateShapes() {
+	for _, id := range slices.Sorted(maps.Keys(s.Trips)) {
+		t := s.Trips[id]
+		if _, ok := s.Shapes[t.ShapeID]; t.ShapeID != "" && !ok {
+			s.errors.add(fmt.Errorf("trips.txt: trip %s: unknown shape ID: %s", t.ID, t.ShapeID))
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package gtfs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShapePoints(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	b := bytes.NewBufferString("shape_id,shape_pt_lat,shape_pt_lon,shape_pt_sequence,shape_dist_traveled\n" +
+		"SH1,34.05,-118.25,1,0\n" +
+		"SH1,,-118.24,2,\n" +
+		"SH1,34.07,-118.23,3,-1\n")
+
+	records := map[string]ShapePoint{}
+	var errs, warnings errorList
+	parse(b, records, &errs, &warnings)
+
+	lat, lon, dist := 34.05, -118.25, 0.0
+	assert.Equal([]string{
+		"invalid record: invalid shape point coordinates",
+		"invalid record: shape distance traveled must be greater than or equal to 0",
+	}, errorStrings(errs))
+	assert.Equal(map[string]ShapePoint{
+		"SH1-1": {ShapeID: "SH1", Coords: Coords{Latitude: &lat, Longitude: &lon}, Sequence: 1, DistTraveled: &dist},
+	}, records)
+
+	// a repeated sequence never reaches buildShapes
+	b = bytes.NewBufferString("shape_id,shape_pt_lat,shape_pt_lon,shape_pt_sequence\n" +
+		"SH1,34.05,-118.25,1\n" +
+		"SH1,34.06,-118.24,1\n")
+
+	records = map[string]ShapePoint{}
+	errs = nil
+	parse(b, records, &errs, &warnings)
+
+	assert.Equal([]string{"duplicate key: SH1-1"}, errorStrings(errs))
+	assert.Len(records, 1)
+}
+
+func TestBuildShapes(t *testing.T) {
+	t.Parallel()
+
+	point := func(id string, seq int, dist *float64) ShapePoint {
+		lat, lon := 34.0+float64(seq)/100, -118.0
+		return ShapePoint{ShapeID: id, Coords: Coords{Latitude: &lat, Longitude: &lon}, Sequence: seq, DistTraveled: dist}
+	}
+	dist := func(d float64) *float64 { return &d }
+
+	testCases := map[string]struct {
+		points []ShapePoint
+		shapes map[string][]int
+		errs   []string
+	}{
+		"sorted by sequence": {
+			points: []ShapePoint{point("SH1", 20, nil), point("SH1", 5, nil), point("SH2", 1, nil), point("SH1", 10, nil), point("SH2", 2, nil)},
+			shapes: map[string][]int{"SH1": {5, 10, 20}, "SH2": {1, 2}},
+		},
+		"increasing distances": {
+			points: []ShapePoint{point("SH1", 1, dist(0)), point("SH1", 2, nil), point("SH1", 3, dist(1.5))},
+			shapes: map[string][]int{"SH1": {1, 2, 3}},
+		},
+		"distance decreases": {
+			points: []ShapePoint{point("SH1", 1, dist(2)), point("SH1", 2, nil), point("SH1", 3, dist(1.5))},
+			shapes: map[string][]int{"SH1": {1, 2, 3}},
+			errs:   []string{"shape SH1: distance traveled at sequence 3 does not increase"},
+		},
+		"distance repeats": {
+			points: []ShapePoint{point("SH1", 1, dist(0)), point("SH1", 2, dist(0))},
+			shapes: map[string][]int{"SH1": {1, 2}},
+			errs:   []string{"shape SH1: distance traveled at sequence 2 does not increase"},
+		},
+		"single point": {
+			points: []ShapePoint{point("SH1", 1, nil), point("SH2", 1, nil), point("SH2", 2, nil)},
+			shapes: map[string][]int{"SH1": {1}, "SH2": {1, 2}},
+			errs:   []string{"shape SH1 has fewer than 2 points"},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			points := map[string]ShapePoint{}
+			for _, p := range tc.points {
+				points[p.key()] = p
+			}
+
+			shapes, errs := buildShapes(points)
+
+			sequences := map[string][]int{}
+			for id, s := range shapes {
+				assert.Equal(id, s.ID)
+				for _, p := range s.Points {
+					sequences[id] = append(sequences[id], p.Sequence)
+				}
+			}
+			assert.Equal(tc.shapes, sequences)
+			assert.ElementsMatch(tc.errs, errorStrings(errs))
+		})
+	}
+}
+
+func TestTripShape(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s := GTFSSchedule{
+		Trips: map[string]Trip{
+			"T1": {ID: "T1", ShapeID: "SH1"},
+			"T2": {ID: "T2"},
+			"T3": {ID: "T3", ShapeID: "SH9"},
+		},
+		Shapes: map[string]Shape{
+			"SH1": {ID: "SH1", Points: []ShapePoint{{ShapeID: "SH1", Sequence: 1}, {ShapeID: "SH1", Sequence: 2}}},
+		},
+	}
+
+	shape, ok := s.TripShape(s.Trips["T1"])
+	assert.True(ok)
+	assert.Equal(s.Shapes["SH1"], shape)
+
+	_, ok = s.TripShape(s.Trips["T2"])
+	assert.False(ok)
+
+	_, ok = s.TripShape(s.Trips["T3"])
+	assert.False(ok)
+
+	s.validate()
+	assert.Equal([]string{"trips.txt: trip T3: unknown shape ID: SH9"}, errorStrings(s.errors))
+}
@@ -5,7 +5,7 @@ import (
 )
 
 type Stop struct {
-	ID                 string `json:"stopId" csv:"stop_id"`
+	ID                 string `json:"stopId" csv:"stop_id,required"`
 	Code               string `json:"stopCode,omitempty" csv:"stop_code"`
 	Name               string `json:"stopName" csv:"stop_name"`
 	TTSName            string `json:"TTSStopName,omitempty" csv:"tts_stop_name"`
@@ -14,7 +14,7 @@ type Stop struct {
 	Longitude          string `json:"longitude" csv:"stop_lon"`
 	ZoneID             string `json:"zoneId,omitempty" csv:"zone_id"`
 	URL                string `json:"stopUrl,omitempty" csv:"stop_url"`
-	LocationType       int    `json:"locationType,omitempty" csv:"location_type"`
+	LocationType       int    `json:"locationType,omitempty" csv:"location_type,default=0"`
 	ParentStation      string `json:"parentStation" csv:"parent_station"`
 	Timezone           string `json:"stopTimezone,omitempty" csv:"stop_timezone"`
 	WheelchairBoarding string `json:"wheelchairBoarding,omitempty" csv:"wheelchair_boarding"`
@@ -29,9 +29,6 @@ func (s Stop) key() string {
 func (s Stop) validate() errorList {
 	var errs errorList
 
-	if s.ID == "" {
-		errs.add(fmt.Errorf("stop ID is required"))
-	}
 	if s.Name == "" {
 		if s.LocationType == StopPlatform || s.LocationType == Station || s.LocationType == EntranceExit {
 			errs.add(fmt.Errorf("stop name is required for location type %d", s.LocationType))
@@ -48,3 +45,21 @@ func (s Stop) validate() errorList {
 
 	return errs
 }
+
+func (s Stop) AfterUnmarshalCSV() error {
+	return s.validate().err()
+}
+
+// stopAncestors returns the stop with ID id and the IDs of the stops it is
+// within, following parent stations, innermost first.
+func stopAncestors(stops map[string]Stop, id string) []string {
+	ids := []string{id}
+	for len(ids) <= len(stops) {
+		s, ok := stops[ids[len(ids)-1]]
+		if !ok || s.ParentStation == "" {
+			break
+		}
+		ids = append(ids, s.ParentStation)
+	}
+	return ids
+}
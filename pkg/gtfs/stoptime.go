@@ -5,13 +5,13 @@ import (
 )
 
 type StopTime struct {
-	TripID                   string   `json:"tripId" csv:"trip_id"`
+	TripID                   string   `json:"tripId" csv:"trip_id,required"`
 	ArrivalTime              Time     `json:"arrivalTime,omitempty" csv:"arrival_time"`
 	DepartureTime            Time     `json:"departureTime,omitempty" csv:"departure_time"`
 	StopID                   string   `json:"stopId" csv:"stop_id"`
 	LocationGroupID          string   `json:"locationGroupId" csv:"location_group_id"`
 	LocationID               string   `json:"locationId" csv:"location_id"`
-	StopSequence             int      `json:"stopSequence" csv:"stop_sequence"`
+	StopSequence             int      `json:"stopSequence" csv:"stop_sequence,required"`
 	StopHeadsign             string   `json:"stopHeadsign" csv:"stop_headsign"`
 	StartPickupDropOffWindow Time     `json:"startPickupDropOffWindow" csv:"start_pickup_drop_off_window"`
 	EndPickupDropOffWindow   Time     `json:"endPickupDropOffWindow" csv:"end_pickup_drop_off_window"`
@@ -32,9 +32,6 @@ func (st StopTime) key() string {
 func (st StopTime) validate() errorList {
 	var errs errorList
 
-	if st.TripID == "" {
-		errs.add(fmt.Errorf("trip ID is required"))
-	}
 	if st.StopSequence < 0 {
 		errs.add(fmt.Errorf("stop sequence must be greater than or equal to 0"))
 	}
@@ -44,3 +41,7 @@ func (st StopTime) validate() errorList {
 
 	return errs
 }
+
+func (st StopTime) AfterUnmarshalCSV() error {
+	return st.validate().err()
+}
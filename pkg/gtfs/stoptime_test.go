@@ -0,0 +1,72 @@
+package gtfs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bridgelightcloud/bogie/pkg/csvmum"
+)
+
+// stopTimesInput returns a stop_times.txt of n trips of 40 stops each.
+func stopTimesInput(n int) string {
+	var b strings.Builder
+	b.WriteString("trip_id,arrival_time,departure_time,stop_id,stop_sequence,stop_headsign,pickup_type,drop_off_type,shape_dist_traveled,timepoint\n")
+	for trip := range n {
+		for seq := range 40 {
+			arrival := 6*3600 + trip*20 + seq*90
+			fmt.Fprintf(&b, "T%d,%s,%s,S%d,%d,Downtown,0,0,%.3f,1\n",
+				trip, clock(arrival), clock(arrival+30), seq, seq+1, float64(seq)*0.45)
+		}
+	}
+	return b.String()
+}
+
+func clock(seconds int) string {
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+func BenchmarkStopTimes(b *testing.B) {
+	input := stopTimesInput(2500)
+
+	b.Run("All", func(b *testing.B) {
+		b.SetBytes(int64(len(input)))
+		for range b.N {
+			m, err := csvmum.NewUnmarshaler[StopTime](strings.NewReader(input))
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, err := range m.All() {
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	for _, unordered := range []bool{false, true} {
+		for _, workers := range []int{1, 2, 4, 8} {
+			name := fmt.Sprintf("Parallel/%d", workers)
+			opts := []csvmum.Option{csvmum.WithWorkers(workers)}
+			if unordered {
+				name = fmt.Sprintf("Unordered/%d", workers)
+				opts = append(opts, csvmum.Unordered())
+			}
+
+			b.Run(name, func(b *testing.B) {
+				b.SetBytes(int64(len(input)))
+				for range b.N {
+					m, err := csvmum.NewUnmarshaler[StopTime](strings.NewReader(input), opts...)
+					if err != nil {
+						b.Fatal(err)
+					}
+					for _, err := range m.Parallel() {
+						if err != nil {
+							b.Fatal(err)
+						}
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package gtfs
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+type Transfer struct {
+	FromStopID      string `json:"fromStopId,omitempty" csv:"from_stop_id"`
+	ToStopID        string `json:"toStopId,omitempty" csv:"to_stop_id"`
+	FromRouteID     string `json:"fromRouteId,omitempty" csv:"from_route_id"`
+	ToRouteID       string `json:"toRouteId,omitempty" csv:"to_route_id"`
+	FromTripID      string `json:"fromTripId,omitempty" csv:"from_trip_id"`
+	ToTripID        string `json:"toTripId,omitempty" csv:"to_trip_id"`
+	TransferType    int    `json:"transferType" csv:"transfer_type,required"`
+	MinTransferTime *int   `json:"minTransferTime,omitempty" csv:"min_transfer_time"`
+}
+
+func (t Transfer) key() string {
+	return fmt.Sprintf("%s-%s-%s-%s-%s-%s", t.FromStopID, t.ToStopID, t.FromRouteID, t.ToRouteID, t.FromTripID, t.ToTripID)
+}
+
+func (t Transfer) validate() errorList {
+	var errs errorList
+
+	if t.TransferType < TransferType.L || t.TransferType > TransferType.U {
+		errs.add(fmt.Errorf("invalid transfer type: %d", t.TransferType))
+	}
+
+	switch t.TransferType {
+	case TimedTransfer, MinimumTimeTransfer, NoTransfer:
+		if t.FromStopID == "" || t.ToStopID == "" {
+			errs.add(fmt.Errorf("from and to stop IDs are required for transfer type %d", t.TransferType))
+		}
+	case InSeatTransfer, ReboardTransfer:
+		if t.FromTripID == "" || t.ToTripID == "" {
+			errs.add(fmt.Errorf("from and to trip IDs are required for transfer type %d", t.TransferType))
+		}
+	}
+
+	if t.MinTransferTime != nil && *t.MinTransferTime < 0 {
+		errs.add(fmt.Errorf("min transfer time must be greater than or equal to 0"))
+	}
+
+	return errs
+}
+
+func (t Transfer) AfterUnmarshalCSV() error {
+	return t.validate().err()
+}
+
+// specificity ranks t among the transfers that match a connection, following
+// the order of precedence of the reference: rules naming both trips first,
+// then the from trip and to route, the from route and to trip, the from trip,
+// the to trip, both routes, the from route, the to route, and lastly only
+// stops.
+func (t Transfer) specificity() int {
+	fromTrip, toTrip := t.FromTripID != "", t.ToTripID != ""
+	fromRoute, toRoute := t.FromRouteID != "", t.ToRouteID != ""
+
+	switch {
+	case fromTrip && toTrip:
+		return 8
+	case fromTrip && toRoute:
+		return 7
+	case fromRoute && toTrip:
+		return 6
+	case fromTrip:
+		return 5
+	case toTrip:
+		return 4
+	case fromRoute && toRoute:
+		return 3
+	case fromRoute:
+		return 2
+	case toRoute:
+		return 1
+	}
+	return 0
+}
+
+// TransferResolver finds the transfer rule that applies to a connection
+// between two trips.
+type TransferResolver struct {
+	stops map[string]Stop
+	// transfers holds the transfers by from stop ID, which may be empty
+	transfers map[string][]Transfer
+}
+
+// TransferResolver returns a resolver for the transfers of the schedule.
+func (s GTFSSchedule) TransferResolver() *TransferResolver {
+	r := &TransferResolver{stops: s.Stops, transfers: make(map[string][]Transfer)}
+
+	for _, k := range slices.Sorted(maps.Keys(s.Transfers)) {
+		t := s.Transfers[k]
+		r.transfers[t.FromStopID] = append(r.transfers[t.FromStopID], t)
+	}
+
+	return r
+}
+
+// Resolve returns the transfer rule for riders arriving at fromStop on from
+// and departing from toStop on to, and whether there is one. Rules for a
+// station apply to its platforms. When several rules apply, the most specific
+// is returned: rules naming trips before rules naming routes, and those before
+// rules naming only stops, with the from side before the to side, and rules
+// for the stops themselves before rules for their stations.
+func (r *TransferResolver) Resolve(from Trip, fromStop string, to Trip, toStop string) (Transfer, bool) {
+	var best Transfer
+	bestRank, bestStops := -1, -1
+
+	for _, id := range append(r.ancestors(fromStop), "") {
+		for _, t := range r.transfers[id] {
+			if !matchID(t.FromTripID, from.ID) || !matchID(t.ToTripID, to.ID) ||
+				!matchID(t.FromRouteID, from.RouteID) || !matchID(t.ToRouteID, to.RouteID) {
+				continue
+			}
+
+			fromScore, ok := r.stopScore(t.FromStopID, fromStop)
+			if !ok {
+				continue
+			}
+			toScore, ok := r.stopScore(t.ToStopID, toStop)
+			if !ok {
+				continue
+			}
+
+			rank, stops := t.specificity(), fromScore+toScore
+			if c := cmp.Or(cmp.Compare(rank, bestRank), cmp.Compare(stops, bestStops)); c > 0 {
+				best, bestRank, bestStops = t, rank, stops
+			}
+		}
+	}
+
+	return best, bestRank >= 0
+}
+
+// ancestors returns stop and the stations it is within, innermost first.
+func (r *TransferResolver) ancestors(stop string) []string {
+	return stopAncestors(r.stops, stop)
+}
+
+// stopScore reports whether a rule for ruleStop applies at stop, and how
+// closely: rules for the stop itself score highest, then those for each
+// station further out, then rules for any stop.
+func (r *TransferResolver) stopScore(ruleStop, stop string) (int, bool) {
+	if ruleStop == "" {
+		return 0, true
+	}
+
+	ancestors := r.ancestors(stop)
+	if i := slices.Index(ancestors, ruleStop); i >= 0 {
+		return len(r.stops) + 1 - i, true
+	}
+	return 0, false
+}
+
+func matchID(rule, id string) bool {
+	return rule == "" || rule == id
+}
+
+// validateTransfers checks that transfers refer to stops, routes and trips.
+func (s *GTFSSchedule) validateTransfers() {
+	for _, k := range slices.Sorted(maps.Keys(s.Transfers)) {
+		t := s.Transfers[k]
+
+		for _, id := range []string{t.FromStopID, t.ToStopID} {
+			if _, ok := s.Stops[id]; id != "" && !ok {
+				s.errors.add(fmt.Errorf("transfers.txt: unknown stop ID: %s", id))
+			}
+		}
+		for _, id := range []string{t.FromRouteID, t.ToRouteID} {
+			if _, ok := s.Routes[id]; id != "" && !ok {
+				s.errors.add(fmt.Errorf("transfers.txt: unknown route ID: %s", id))
+			}
+		}
+		for _, id := range []string{t.FromTripID, t.ToTripID} {
+			if _, ok := s.Trips[id]; id != "" && !ok {
+				s.errors.add(fmt.Errorf("transfers.txt: unknown trip ID: %s", id))
+			}
+		}
+	}
+}
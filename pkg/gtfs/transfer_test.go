@@ -0,0 +1,169 @@
+package gtfs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTransfers(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	b := bytes.NewBufferString("from_stop_id,to_stop_id,from_route_id,to_route_id,from_trip_id,to_trip_id,transfer_type,min_transfer_time\n" +
+		"S1,S2,,,,,2,180\n" +
+		",,,,T1,T2,4,\n" +
+		"S1,,,,,,1,\n" +
+		"S1,S2,,,T1,,5,\n" +
+		"S1,S3,,,,,6,-1\n")
+
+	records := map[string]Transfer{}
+	var errs, warnings errorList
+	parse(b, records, &errs, &warnings)
+
+	minTime := 180
+	assert.Equal([]string{
+		"invalid record: from and to stop IDs are required for transfer type 1",
+		"invalid record: from and to trip IDs are required for transfer type 5",
+		"invalid record: invalid transfer type: 6",
+		"invalid record: min transfer time must be greater than or equal to 0",
+	}, errorStrings(errs))
+	assert.Equal(map[string]Transfer{
+		"S1-S2----": {FromStopID: "S1", ToStopID: "S2", TransferType: MinimumTimeTransfer, MinTransferTime: &minTime},
+		"----T1-T2": {FromTripID: "T1", ToTripID: "T2", TransferType: InSeatTransfer},
+	}, records)
+}
+
+func TestTransferResolver(t *testing.T) {
+	t.Parallel()
+
+	s := GTFSSchedule{
+		Stops: map[string]Stop{
+			"ST1": {ID: "ST1", LocationType: Station},
+			"P1":  {ID: "P1", ParentStation: "ST1"},
+			"P2":  {ID: "P2", ParentStation: "ST1"},
+			"BA1": {ID: "BA1", LocationType: BoardingArea, ParentStation: "P1"},
+			"P3":  {ID: "P3"},
+		},
+		Trips: map[string]Trip{
+			"T1": {ID: "T1", RouteID: "R1"},
+			"T2": {ID: "T2", RouteID: "R2"},
+			"T3": {ID: "T3", RouteID: "R2"},
+			"T4": {ID: "T4", RouteID: "R3"},
+			"T5": {ID: "T5", RouteID: "R3"},
+			"T6": {ID: "T6", RouteID: "R4"},
+		},
+	}
+
+	transfers := []Transfer{
+		{FromStopID: "ST1", ToStopID: "ST1", TransferType: MinimumTimeTransfer},
+		{FromStopID: "P1", ToStopID: "P2", TransferType: RecommendedTransfer},
+		{FromStopID: "ST1", ToStopID: "ST1", FromRouteID: "R1", TransferType: TimedTransfer},
+		{FromStopID: "ST1", ToStopID: "ST1", FromRouteID: "R1", ToRouteID: "R2", TransferType: MinimumTimeTransfer},
+		{FromStopID: "ST1", ToStopID: "ST1", ToTripID: "T3", TransferType: NoTransfer},
+		{FromStopID: "ST1", ToStopID: "ST1", FromRouteID: "R1", ToTripID: "T3", TransferType: TimedTransfer},
+		{FromTripID: "T1", ToTripID: "T4", TransferType: InSeatTransfer},
+		{FromStopID: "ST1", ToStopID: "ST1", FromTripID: "T1", ToRouteID: "R4", TransferType: MinimumTimeTransfer},
+		{FromStopID: "P1", ToStopID: "P2", FromRouteID: "R1", ToTripID: "T6", TransferType: NoTransfer},
+		{FromStopID: "ST1", ToStopID: "ST1", FromTripID: "T6", TransferType: TimedTransfer},
+	}
+	s.Transfers = map[string]Transfer{}
+	for _, tr := range transfers {
+		s.Transfers[tr.key()] = tr
+	}
+
+	r := s.TransferResolver()
+
+	testCases := map[string]struct {
+		from, to         string
+		fromStop, toStop string
+		want             int
+		ok               bool
+	}{
+		"both trips": {
+			from: "T1", fromStop: "P1", to: "T4", toStop: "P3",
+			want: 6, ok: true,
+		},
+		"from trip and to route before from route and to trip": {
+			from: "T1", fromStop: "P1", to: "T6", toStop: "P2",
+			want: 7, ok: true,
+		},
+		"route and trip": {
+			from: "T1", fromStop: "P1", to: "T3", toStop: "P2",
+			want: 5, ok: true,
+		},
+		"from trip before to trip": {
+			from: "T6", fromStop: "P1", to: "T3", toStop: "P2",
+			want: 9, ok: true,
+		},
+		"one trip": {
+			from: "T2", fromStop: "P1", to: "T3", toStop: "P2",
+			want: 4, ok: true,
+		},
+		"both routes": {
+			from: "T1", fromStop: "P1", to: "T2", toStop: "P2",
+			want: 3, ok: true,
+		},
+		"one route": {
+			from: "T1", fromStop: "P2", to: "T5", toStop: "P1",
+			want: 2, ok: true,
+		},
+		"platforms before station": {
+			from: "T2", fromStop: "P1", to: "T4", toStop: "P2",
+			want: 1, ok: true,
+		},
+		"station": {
+			from: "T2", fromStop: "P2", to: "T4", toStop: "P1",
+			want: 0, ok: true,
+		},
+		"boarding area within station": {
+			from: "T2", fromStop: "BA1", to: "T4", toStop: "P2",
+			want: 1, ok: true,
+		},
+		"no rule": {
+			from: "T2", fromStop: "P3", to: "T4", toStop: "P1",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			got, ok := r.Resolve(s.Trips[tc.from], tc.fromStop, s.Trips[tc.to], tc.toStop)
+
+			assert.Equal(tc.ok, ok)
+			if tc.ok {
+				assert.Equal(transfers[tc.want], got)
+			} else {
+				assert.Equal(Transfer{}, got)
+			}
+		})
+	}
+}
+
+func TestValidateTransfers(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	s := GTFSSchedule{
+		Stops:  map[string]Stop{"S1": {ID: "S1"}},
+		Routes: map[string]Route{"R1": {ID: "R1"}},
+		Trips:  map[string]Trip{"T1": {ID: "T1", RouteID: "R1"}},
+		Transfers: map[string]Transfer{
+			"S1-S9----": {FromStopID: "S1", ToStopID: "S9", TransferType: RecommendedTransfer},
+			"--R1-R9--": {FromRouteID: "R1", ToRouteID: "R9"},
+			"----T9-T1": {FromTripID: "T9", ToTripID: "T1", TransferType: InSeatTransfer},
+		},
+	}
+
+	s.validate()
+
+	assert.Equal([]string{
+		"transfers.txt: unknown trip ID: T9",
+		"transfers.txt: unknown route ID: R9",
+		"transfers.txt: unknown stop ID: S9",
+	}, errorStrings(s.errors))
+}
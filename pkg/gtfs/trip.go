@@ -1,20 +1,16 @@
 package gtfs
 
-import (
-	"fmt"
-)
-
 type Trip struct {
-	RouteID              string `json:"routeId,omitempty" csv:"route_id,omitempty"`
-	ServiceID            string `json:"serviceId,omitempty" csv:"service_id,omitempty"`
-	ID                   string `json:"tripId" csv:"trip_id"`
+	RouteID              string `json:"routeId,omitempty" csv:"route_id,required"`
+	ServiceID            string `json:"serviceId,omitempty" csv:"service_id,required"`
+	ID                   string `json:"tripId" csv:"trip_id,required"`
 	Headsign             string `json:"tripHeadsign" csv:"trip_headsign"`
 	ShortName            string `json:"tripShortName" csv:"trip_short_name"`
 	DirectionID          int    `json:"directionId" csv:"direction_id"`
 	BlockID              string `json:"blockId" csv:"block_id"`
 	ShapeID              string `json:"shapeId" csv:"shape_id"`
-	WheelchairAccessible int    `json:"wheelchairAccessible" csv:"wheelchair_accessible"`
-	BikesAllowed         int    `json:"bikesAllowed" csv:"bikes_allowed"`
+	WheelchairAccessible int    `json:"wheelchairAccessible" csv:"wheelchair_accessible,default=0"`
+	BikesAllowed         int    `json:"bikesAllowed" csv:"bikes_allowed,default=0"`
 }
 
 func (t Trip) key() string {
@@ -23,16 +19,9 @@ func (t Trip) key() string {
 
 func (t Trip) validate() errorList {
 	var errs errorList
-
-	if t.ID == "" {
-		errs.add(fmt.Errorf("trip ID is required"))
-	}
-	if t.ServiceID == "" {
-		errs.add(fmt.Errorf("trip service id is required"))
-	}
-	if t.ID == "" {
-		errs.add(fmt.Errorf("trip ID is required"))
-	}
-
 	return errs
 }
+
+func (t Trip) AfterUnmarshalCSV() error {
+	return t.validate().err()
+}
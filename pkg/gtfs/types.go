@@ -1,7 +1,9 @@
 package gtfs
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -195,6 +197,104 @@ func ParseCurrencyCode(v string, c *string) error {
 	return nil
 }
 
+// Decimal is a decimal number held exactly, as in a currency amount. Its
+// scale is the number of digits after the decimal point it was given with.
+type Decimal struct {
+	value int64
+	scale int
+}
+
+// NewDecimal returns value/10^scale as a Decimal.
+func NewDecimal(value int64, scale int) Decimal {
+	return Decimal{value: value, scale: scale}
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(text []byte) error {
+	whole, frac, found := strings.Cut(string(text), ".")
+	if found && frac == "" || strings.ContainsAny(frac, "+-") {
+		return fmt.Errorf("invalid decimal value: %s", text)
+	}
+
+	v, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid decimal value: %s", text)
+	}
+
+	*d = Decimal{value: v, scale: len(frac)}
+	return nil
+}
+
+func (d Decimal) String() string {
+	s := strconv.FormatInt(d.value, 10)
+	if d.scale == 0 {
+		return s
+	}
+
+	sign := ""
+	if d.value < 0 {
+		sign, s = "-", s[1:]
+	}
+	if len(s) <= d.scale {
+		s = strings.Repeat("0", d.scale-len(s)+1) + s
+	}
+	return sign + s[:len(s)-d.scale] + "." + s[len(s)-d.scale:]
+}
+
+// IsNegative reports whether d is less than 0.
+func (d Decimal) IsNegative() bool {
+	return d.value < 0
+}
+
+// Money is an amount in a currency, held exactly as a count of the minor
+// units of the currency, such as cents.
+type Money struct {
+	Minor    int64  `json:"minor"`
+	Currency string `json:"currency"`
+}
+
+// NewMoney returns the amount d of currency, an ISO 4217 code. It is an error
+// if d is more precise than the minor units of the currency allow, as 1.005
+// USD is.
+func NewMoney(d Decimal, currency string) (Money, error) {
+	digits, ok := validCurrencyCodes[currency]
+	if !ok {
+		return Money{}, fmt.Errorf("invalid currency code: %s", currency)
+	}
+
+	minor := d.value
+	for scale := d.scale; scale != digits; {
+		if scale < digits {
+			if minor > math.MaxInt64/10 || minor < math.MinInt64/10 {
+				return Money{}, fmt.Errorf("amount %s is out of range for %s", d, currency)
+			}
+			minor *= 10
+			scale++
+			continue
+		}
+		if minor%10 != 0 {
+			return Money{}, fmt.Errorf("amount %s has more than %d decimal places for %s", d, digits, currency)
+		}
+		minor /= 10
+		scale--
+	}
+
+	return Money{Minor: minor, Currency: currency}, nil
+}
+
+// Decimal returns the amount of m, with as many decimal places as the minor
+// units of its currency.
+func (m Money) Decimal() Decimal {
+	return Decimal{value: m.Minor, scale: validCurrencyCodes[m.Currency]}
+}
+
+func (m Money) String() string {
+	return m.Decimal().String() + " " + m.Currency
+}
+
 type Date struct {
 	time.Time
 }
@@ -233,15 +333,22 @@ type Time struct {
 
 var timeFormat = "15:04:05"
 
+// MarshalText writes t as the time since the start of its service day, so
+// that times past midnight have hours of 24 and more. Times parsed by
+// UnmarshalText, and those shifted from them, fall on the days from January 1
+// of year 0, and any number of days past it is counted. Other times are taken
+// to be on the day after the service day starts if they are not on the first
+// of the month.
 func (t Time) MarshalText() ([]byte, error) {
-	timeStr := t.Format(timeFormat)
-
-	if d := t.Time.Day(); d > 1 {
-		hrs := strconv.Itoa(t.Hour() + 24)
-		return []byte(hrs + timeStr[2:]), nil
+	days := 0
+	if y, m, d := t.Date(); y == 0 && m == time.January {
+		days = d - 1
+	} else if d > 1 {
+		days = 1
 	}
 
-	return []byte(timeStr), nil
+	timeStr := t.Format(timeFormat)
+	return fmt.Appendf(nil, "%02d%s", t.Hour()+24*days, timeStr[2:]), nil
 }
 
 func (t *Time) UnmarshalText(text []byte) error {
@@ -275,6 +382,14 @@ func (t *Time) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// add returns t shifted by d, or t if it is not set.
+func (t Time) add(d time.Duration) Time {
+	if t.IsZero() {
+		return t
+	}
+	return Time{t.Time.Add(d)}
+}
+
 func (t Time) MarshalJSON() ([]byte, error) {
 	if t.IsZero() {
 		return []byte("null"), nil
@@ -294,12 +409,30 @@ func (t *Time) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Coords is a WGS 84 position. Either coordinate may be missing, as both are
+// optional for some records.
+type Coords struct {
+	Latitude  *float64 `json:"latitude,omitempty" csv:"lat"`
+	Longitude *float64 `json:"longitude,omitempty" csv:"lon"`
+}
+
+// IsValid reports whether both coordinates are present and in range.
+func (c Coords) IsValid() bool {
+	return c.Latitude != nil && c.Longitude != nil &&
+		*c.Latitude >= -90 && *c.Latitude <= 90 &&
+		*c.Longitude >= -180 && *c.Longitude <= 180
+}
+
 type enumBounds struct {
 	L int
 	U int
 }
 
 var (
+	AttributionRole enumBounds = enumBounds{0, 1}
+	NoRole          int        = 0
+	HasRole         int        = 1
+
 	Availability enumBounds = enumBounds{0, 1}
 	Available    int        = 0
 	Unavailable  int        = 1
@@ -322,14 +455,40 @@ var (
 	OneDirection      int        = 0
 	OppositeDirection int        = 1
 
+	ExactTimes     enumBounds = enumBounds{0, 1}
+	FrequencyBased int        = 0
+	ScheduleBased  int        = 1
+
 	ExceptionType enumBounds = enumBounds{1, 2}
 	Added         int        = 1
 	Removed       int        = 2
 
+	PathwayMode    enumBounds = enumBounds{1, 7}
+	Walkway        int        = 1
+	Stairs         int        = 2
+	MovingSidewalk int        = 3
+	Escalator      int        = 4
+	Elevator       int        = 5
+	FareGate       int        = 6
+	ExitGate       int        = 7
+
+	IsBidirectional enumBounds = enumBounds{0, 1}
+	Unidirectional  int        = 0
+	Bidirectional   int        = 1
+
 	Timepoint       enumBounds = enumBounds{0, 1}
 	ApproximateTime int        = 0
 	ExactTime       int        = 1
 
+	PaymentMethod     enumBounds = enumBounds{0, 1}
+	PayOnBoard        int        = 0
+	PayBeforeBoarding int        = 1
+
+	FareTransfers    enumBounds = enumBounds{0, 2}
+	NoFareTransfers  int        = 0
+	OneFareTransfer  int        = 1
+	TwoFareTransfers int        = 2
+
 	LocationType enumBounds = enumBounds{0, 4}
 	StopPlatform int        = 0
 	Station      int        = 1
@@ -337,6 +496,14 @@ var (
 	GenericNode  int        = 3
 	BoardingArea int        = 4
 
+	TransferType        enumBounds = enumBounds{0, 5}
+	RecommendedTransfer int        = 0
+	TimedTransfer       int        = 1
+	MinimumTimeTransfer int        = 2
+	NoTransfer          int        = 3
+	InSeatTransfer      int        = 4
+	ReboardTransfer     int        = 5
+
 	WheelchairAccessible            enumBounds = enumBounds{0, 2}
 	UnknownAccessibility            int        = 0
 	AtLeastOneWheelchairAccomodated int        = 1
@@ -352,3 +519,19 @@ func (e *errorList) add(err error) error {
 	*e = append(*e, err)
 	return err
 }
+
+// err returns the list as an error, or nil if it is empty.
+func (e errorList) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e errorList) Error() string {
+	return errors.Join(e...).Error()
+}
+
+func (e errorList) Unwrap() []error {
+	return e
+}
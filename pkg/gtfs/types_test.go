@@ -323,6 +323,16 @@ func TestTimeMarshalText(t *testing.T) {
 		time: Time{Time: time.Date(1, 1, 2, 1, 34, 22, 0, time.UTC)},
 		out:  []byte("25:34:22"),
 		err:  nil,
+	}, {
+		name: "time over 48 hrs",
+		time: Time{Time: time.Date(0, 1, 3, 0, 4, 30, 0, time.UTC)},
+		out:  []byte("48:04:30"),
+		err:  nil,
+	}, {
+		name: "time in another month",
+		time: Time{Time: time.Date(2024, 3, 5, 7, 8, 9, 0, time.UTC)},
+		out:  []byte("31:08:09"),
+		err:  nil,
 	}, {
 		name: "zero time",
 		time: Time{Time: time.Time{}},
@@ -480,6 +490,47 @@ func TestTimeUnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestCoordsIsValid(t *testing.T) {
+	t.Parallel()
+
+	f := func(v float64) *float64 { return &v }
+
+	tt := []struct {
+		name   string
+		coords Coords
+		valid  bool
+	}{{
+		name:   "valid",
+		coords: Coords{Latitude: f(34.05), Longitude: f(-118.25)},
+		valid:  true,
+	}, {
+		name:   "bounds",
+		coords: Coords{Latitude: f(-90), Longitude: f(180)},
+		valid:  true,
+	}, {
+		name:   "missing latitude",
+		coords: Coords{Longitude: f(-118.25)},
+	}, {
+		name:   "missing longitude",
+		coords: Coords{Latitude: f(34.05)},
+	}, {
+		name:   "latitude out of range",
+		coords: Coords{Latitude: f(90.5), Longitude: f(0)},
+	}, {
+		name:   "longitude out of range",
+		coords: Coords{Latitude: f(0), Longitude: f(-180.5)},
+	}}
+
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.valid, tc.coords.IsValid())
+		})
+	}
+}
+
 func TestErrorList(t *testing.T) {
 	t.Parallel()
 
@@ -524,3 +575,145 @@ func TestErrorList(t *testing.T) {
 		})
 	}
 }
+
+func TestDecimalUnmarshalText(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		value       string
+		expectedErr error
+		expected    Decimal
+		text        string
+	}{{
+		value:    "2.50",
+		expected: NewDecimal(250, 2),
+		text:     "2.50",
+	}, {
+		value:    "250",
+		expected: NewDecimal(250, 0),
+		text:     "250",
+	}, {
+		value:    ".05",
+		expected: NewDecimal(5, 2),
+		text:     "0.05",
+	}, {
+		value:    "-0.5",
+		expected: NewDecimal(-5, 1),
+		text:     "-0.5",
+	}, {
+		value:       "2.",
+		expectedErr: fmt.Errorf("invalid decimal value: %s", "2."),
+	}, {
+		value:       "2.5.0",
+		expectedErr: fmt.Errorf("invalid decimal value: %s", "2.5.0"),
+	}, {
+		value:       "1.-5",
+		expectedErr: fmt.Errorf("invalid decimal value: %s", "1.-5"),
+	}, {
+		value:       "two",
+		expectedErr: fmt.Errorf("invalid decimal value: %s", "two"),
+	}, {
+		value:       "",
+		expectedErr: fmt.Errorf("invalid decimal value: %s", ""),
+	}}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.value, func(t *testing.T) {
+			t.Parallel()
+
+			assert := assert.New(t)
+
+			var d Decimal
+			err := d.UnmarshalText([]byte(tc.value))
+
+			assert.Equal(tc.expectedErr, err)
+			assert.Equal(tc.expected, d)
+			if err == nil {
+				text, _ := d.MarshalText()
+				assert.Equal(tc.text, string(text))
+			}
+		})
+	}
+}
+
+func TestNewMoney(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		amount      Decimal
+		currency    string
+		expectedErr error
+		expected    Money
+		text        string
+	}{{
+		amount:   NewDecimal(250, 2),
+		currency: "USD",
+		expected: Money{Minor: 250, Currency: "USD"},
+		text:     "2.50 USD",
+	}, {
+		amount:   NewDecimal(25, 1),
+		currency: "USD",
+		expected: Money{Minor: 250, Currency: "USD"},
+		text:     "2.50 USD",
+	}, {
+		amount:   NewDecimal(2500, 3),
+		currency: "USD",
+		expected: Money{Minor: 250, Currency: "USD"},
+		text:     "2.50 USD",
+	}, {
+		amount:   NewDecimal(250, 0),
+		currency: "JPY",
+		expected: Money{Minor: 250, Currency: "JPY"},
+		text:     "250 JPY",
+	}, {
+		amount:   NewDecimal(1, 0),
+		currency: "KWD",
+		expected: Money{Minor: 1000, Currency: "KWD"},
+		text:     "1.000 KWD",
+	}, {
+		amount:      NewDecimal(2505, 3),
+		currency:    "USD",
+		expectedErr: fmt.Errorf("amount 2.505 has more than 2 decimal places for USD"),
+	}, {
+		amount:      NewDecimal(5, 1),
+		currency:    "JPY",
+		expectedErr: fmt.Errorf("amount 0.5 has more than 0 decimal places for JPY"),
+	}, {
+		amount:      NewDecimal(922337203685477580, 0),
+		currency:    "USD",
+		expectedErr: fmt.Errorf("amount 922337203685477580 is out of range for USD"),
+	}, {
+		amount:      NewDecimal(-9223372036854776, 0),
+		currency:    "KWD",
+		expectedErr: fmt.Errorf("amount -9223372036854776 is out of range for KWD"),
+	}, {
+		amount:   NewDecimal(92233720368547758, 0),
+		currency: "USD",
+		expected: Money{Minor: 9223372036854775800, Currency: "USD"},
+		text:     "92233720368547758.00 USD",
+	}, {
+		amount:      NewDecimal(250, 2),
+		currency:    "usd",
+		expectedErr: fmt.Errorf("invalid currency code: %s", "usd"),
+	}}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.amount.String()+" "+tc.currency, func(t *testing.T) {
+			t.Parallel()
+
+			assert := assert.New(t)
+
+			m, err := NewMoney(tc.amount, tc.currency)
+
+			assert.Equal(tc.expectedErr, err)
+			assert.Equal(tc.expected, m)
+			if err == nil {
+				assert.Equal(tc.text, m.String())
+			}
+		})
+	}
+}
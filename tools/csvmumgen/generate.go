@@ -0,0 +1,614 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type typeKind int
+
+const (
+	kindUnsupported typeKind = iota
+	kindText
+	kindDuration
+	kindString
+	kindInt
+	kindUint
+	kindBool
+	kindFloat
+)
+
+type fieldInfo struct {
+	goName       string
+	name         string
+	omitEmpty    bool
+	required     bool
+	hasDefault   bool
+	defaultValue string
+	parser       string
+	extra        bool
+	hasPrefix    bool
+	prefix       string
+	typ          types.Type
+}
+
+type generator struct {
+	pkg             *types.Package
+	textMarshaler   *types.Interface
+	textUnmarshaler *types.Interface
+	imports         map[string]string
+	buf             bytes.Buffer
+}
+
+func generate(dir, output string, names []string) ([]byte, []string, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot load package: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	files := []*ast.File{}
+	for _, name := range bp.GoFiles {
+		if name == output {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot parse package: %w", err)
+		}
+		files = append(files, f)
+	}
+
+	imp := importer.ForCompiler(fset, "source", nil)
+	conf := types.Config{Importer: imp.(types.ImporterFrom)}
+	pkg, err := conf.Check(bp.ImportPath, fset, files, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot type check package: %w", err)
+	}
+
+	enc, err := imp.Import("encoding")
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot import encoding: %w", err)
+	}
+
+	g := &generator{
+		pkg:             pkg,
+		textMarshaler:   enc.Scope().Lookup("TextMarshaler").Type().Underlying().(*types.Interface),
+		textUnmarshaler: enc.Scope().Lookup("TextUnmarshaler").Type().Underlying().(*types.Interface),
+		imports:         map[string]string{},
+	}
+
+	skipped := []string{}
+	for _, name := range pkg.Scope().Names() {
+		obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+
+		st, ok := named.Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+
+		if len(names) > 0 {
+			if !slices.Contains(names, name) {
+				continue
+			}
+		} else if !hasCSVTag(st) {
+			continue
+		}
+
+		if err := g.generateType(name, st); err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+
+	for _, name := range names {
+		if obj, ok := pkg.Scope().Lookup(name).(*types.TypeName); !ok || !isStruct(obj.Type()) {
+			return nil, nil, fmt.Errorf("%s is not a struct type in %s", name, pkg.Name())
+		}
+	}
+
+	src := &bytes.Buffer{}
+	fmt.Fprintf(src, "// Code generated by csvmumgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(src, "package %s\n\n", pkg.Name())
+
+	body := g.buf.String()
+	for _, path := range []string{"fmt", "math", "strconv", "time", "github.com/bridgelightcloud/bogie/pkg/csvmum"} {
+		name := path[strings.LastIndex(path, "/")+1:]
+		if regexp.MustCompile(`(^|[^\w.])` + name + `\.`).MatchString(body) {
+			g.imports[path] = name
+		}
+	}
+
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if isStdlib(paths[i]) != isStdlib(paths[j]) {
+			return isStdlib(paths[i])
+		}
+		return paths[i] < paths[j]
+	})
+
+	if len(paths) > 0 {
+		fmt.Fprintf(src, "import (\n")
+		for i, path := range paths {
+			if i > 0 && isStdlib(paths[i-1]) != isStdlib(path) {
+				fmt.Fprintf(src, "\n")
+			}
+			fmt.Fprintf(src, "\t%q\n", path)
+		}
+		fmt.Fprintf(src, ")\n\n")
+	}
+
+	src.WriteString(body)
+
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot format generated code: %w\n%s", err, src.Bytes())
+	}
+
+	return out, skipped, nil
+}
+
+func isStdlib(path string) bool {
+	return !strings.Contains(strings.Split(path, "/")[0], ".")
+}
+
+func isStruct(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+func hasCSVTag(st *types.Struct) bool {
+	for i := range st.NumFields() {
+		if _, ok := reflect.StructTag(st.Tag(i)).Lookup("csv"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTag mirrors getExportedName in csvmum.
+func parseTag(f *types.Var, tag string) fieldInfo {
+	fi := fieldInfo{goName: f.Name(), name: "-", typ: f.Type()}
+	if f.Exported() {
+		fi.name = f.Name()
+	}
+
+	if tag, ok := reflect.StructTag(tag).Lookup("csv"); ok {
+		for i, opt := range splitTag(tag) {
+			switch {
+			case i == 0:
+				if opt != "" && f.Exported() {
+					fi.name = opt
+				}
+			case opt == "omitempty":
+				fi.omitEmpty = true
+			case opt == "required":
+				fi.required = true
+			case strings.HasPrefix(opt, "default="):
+				fi.hasDefault = true
+				fi.defaultValue = strings.TrimPrefix(opt, "default=")
+			case opt == "extra":
+				fi.extra = true
+			case strings.HasPrefix(opt, "prefix="):
+				fi.hasPrefix = true
+				fi.prefix = strings.TrimPrefix(opt, "prefix=")
+			case strings.HasPrefix(opt, "parse="):
+				fi.parser = strings.TrimPrefix(opt, "parse=")
+			}
+		}
+	}
+
+	return fi
+}
+
+// splitTag mirrors splitTag in csvmum, where a doubled comma within the
+// options is a comma in an option value.
+func splitTag(tag string) []string {
+	name, rest, ok := strings.Cut(tag, ",")
+	tags := []string{name}
+	if !ok {
+		return tags
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(rest); i++ {
+		switch {
+		case rest[i] != ',':
+			b.WriteByte(rest[i])
+		case i+1 < len(rest) && rest[i+1] == ',':
+			b.WriteByte(',')
+			i++
+		default:
+			tags = append(tags, b.String())
+			b.Reset()
+		}
+	}
+	return append(tags, b.String())
+}
+
+// appendFields mirrors appendFields in csvmum, flattening embedded structs and
+// structs with a prefix= option.
+func (g *generator) appendFields(fields []fieldInfo, st *types.Struct, path, prefix string) []fieldInfo {
+	for i := range st.NumFields() {
+		f := st.Field(i)
+		fi := parseTag(f, st.Tag(i))
+		fi.goName = path + fi.goName
+
+		if g.flattened(f, st.Tag(i), fi) {
+			fields = g.appendFields(fields, f.Type().Underlying().(*types.Struct), fi.goName+".", prefix+fi.prefix)
+			continue
+		}
+
+		// csvmum fills the extra field itself
+		if fi.name == "-" || fi.extra {
+			continue
+		}
+		fi.name = prefix + fi.name
+		fields = append(fields, fi)
+	}
+
+	return fields
+}
+
+// flattened mirrors flattened in csvmum.
+func (g *generator) flattened(f *types.Var, tag string, fi fieldInfo) bool {
+	t := f.Type()
+	if _, ok := t.Underlying().(*types.Struct); !ok || isPointer(t) ||
+		types.Implements(types.NewPointer(t), g.textUnmarshaler) ||
+		types.Implements(types.NewPointer(t), g.textMarshaler) {
+		return false
+	}
+
+	name, _, _ := strings.Cut(reflect.StructTag(tag).Get("csv"), ",")
+	if name == "-" || !(f.Exported() || f.Embedded()) {
+		return false
+	}
+
+	return fi.hasPrefix || (f.Embedded() && name == "")
+}
+
+func isPointer(t types.Type) bool {
+	_, ok := t.(*types.Pointer)
+	return ok
+}
+
+func (g *generator) generateType(name string, st *types.Struct) error {
+	fields := g.appendFields(nil, st, "", "")
+
+	names := map[string]bool{}
+	for _, fi := range fields {
+		if names[fi.name] {
+			return fmt.Errorf("duplicate column %s", fi.name)
+		}
+		names[fi.name] = true
+	}
+
+	columns := &bytes.Buffer{}
+	unmarshal := &bytes.Buffer{}
+	marshal := &bytes.Buffer{}
+
+	for j, fi := range fields {
+		fmt.Fprintf(columns, "%q,", fi.name)
+
+		dec, err := g.decodeField(fi, j)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fi.goName, err)
+		}
+		unmarshal.WriteString(dec)
+
+		enc, err := g.encodeField(fi, j)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fi.goName, err)
+		}
+		marshal.WriteString(enc)
+	}
+
+	fmt.Fprintf(&g.buf, "func (r *%s) CSVColumns() []string {\n", name)
+	fmt.Fprintf(&g.buf, "return []string{%s}\n}\n\n", strings.TrimSuffix(columns.String(), ","))
+
+	fmt.Fprintf(&g.buf, "func (r *%s) UnmarshalCSVRow(record []string, columns []int) error {\n", name)
+	fmt.Fprintf(&g.buf, "%sreturn nil\n}\n\n", unmarshal)
+
+	fmt.Fprintf(&g.buf, "func (r *%s) MarshalCSVRow(row []string) error {\n", name)
+	fmt.Fprintf(&g.buf, "%sreturn nil\n}\n\n", marshal)
+
+	return nil
+}
+
+func (g *generator) decodeField(fi fieldInfo, j int) (string, error) {
+	b := &strings.Builder{}
+	target := "r." + fi.goName
+
+	if fi.hasDefault {
+		fmt.Fprintf(b, "{\nvar s string\nif i := columns[%d]; i != -1 {\ns = record[i]\n}\n", j)
+		fmt.Fprintf(b, "if s == \"\" {\ns = %q\n}\n", fi.defaultValue)
+	} else {
+		fmt.Fprintf(b, "if i := columns[%d]; i != -1 {\ns := record[i]\n", j)
+	}
+
+	if fi.required {
+		fmt.Fprintf(b, "if s == \"\" {\nreturn &csvmum.FieldError{Index: %d, Err: csvmum.ErrRequired}\n}\n", j)
+	}
+
+	if ptr, ok := fi.typ.(*types.Pointer); ok {
+		elem := ptr.Elem()
+		if _, ok := elem.(*types.Pointer); ok {
+			return "", fmt.Errorf("unsupported type %s", fi.typ)
+		}
+
+		dec, err := g.decodeValue("p", elem, j, fi.parser)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(b, "if s != \"\" {\nvar p %s\n%s%s = &p\n}\n", g.typeString(elem), dec, target)
+	} else {
+		dec, err := g.decodeValue(target, fi.typ, j, fi.parser)
+		if err != nil {
+			return "", err
+		}
+		if fi.parser != "" {
+			// named parsers are not called for empty cells
+			dec = fmt.Sprintf("if s != \"\" {\n%s}\n", dec)
+		}
+		b.WriteString(dec)
+	}
+
+	b.WriteString("}\n\n")
+	return b.String(), nil
+}
+
+func (g *generator) decodeValue(target string, t types.Type, j int, parser string) (string, error) {
+	kind, basic := g.classify(t, g.textUnmarshaler)
+
+	// only conversions need the type, and so possibly an import
+	var typ string
+	if basic != nil && parser == "" {
+		typ = g.typeString(t)
+	}
+
+	fieldError := func(cause string) string {
+		return fmt.Sprintf("return &csvmum.FieldError{Index: %d, Err: %s}\n", j, cause)
+	}
+
+	if parser != "" {
+		return fmt.Sprintf("if err := csvmum.Parse(%q, s, &%s); err != nil {\n%s}\n", parser, target, fieldError("err")), nil
+	}
+
+	switch kind {
+	case kindText:
+		return fmt.Sprintf("if err := %s.UnmarshalText([]byte(s)); err != nil {\n%s}\n", target, fieldError("err")), nil
+	case kindDuration:
+		return fmt.Sprintf("v, err := time.ParseDuration(s)\nif err != nil {\n%s}\n%s = v\n",
+			fieldError(`fmt.Errorf("error parsing duration: %w", err)`), target), nil
+	case kindString:
+		return fmt.Sprintf("%s = %s\n", target, convert(typ, "string", "s")), nil
+	case kindInt:
+		return fmt.Sprintf("v, err := strconv.ParseInt(s, 10, %s)\nif err != nil {\n%s}\n%s = %s\n",
+			bits(basic), fieldError(parseError(basic)), target, convert(typ, "int64", "v")), nil
+	case kindUint:
+		return fmt.Sprintf("v, err := strconv.ParseUint(s, 10, %s)\nif err != nil {\n%s}\n%s = %s\n",
+			bits(basic), fieldError(parseError(basic)), target, convert(typ, "uint64", "v")), nil
+	case kindBool:
+		return fmt.Sprintf("v, err := strconv.ParseBool(s)\nif err != nil {\n%s}\n%s = %s\n",
+			fieldError(parseError(basic)), target, convert(typ, "bool", "v")), nil
+	case kindFloat:
+		return fmt.Sprintf("v, err := strconv.ParseFloat(s, %s)\nif err != nil {\n%s}\n%s = %s\n",
+			bits(basic), fieldError(parseError(basic)), target, convert(typ, "float64", "v")), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+func (g *generator) encodeField(fi fieldInfo, j int) (string, error) {
+	b := &strings.Builder{}
+	src := "r." + fi.goName
+
+	var enc string
+	if ptr, ok := fi.typ.(*types.Pointer); ok {
+		elem := ptr.Elem()
+		if _, ok := elem.(*types.Pointer); ok {
+			return "", fmt.Errorf("unsupported type %s", fi.typ)
+		}
+
+		e, err := g.encodeValue(src, "*"+src, elem, j, fi.parser)
+		if err != nil {
+			return "", err
+		}
+		enc = fmt.Sprintf("if %s == nil {\nrow[%d] = \"\"\n} else {\n%s}\n", src, j, e)
+	} else {
+		e, err := g.encodeValue(src, src, fi.typ, j, fi.parser)
+		if err != nil {
+			return "", err
+		}
+		enc = e
+	}
+
+	if fi.omitEmpty {
+		zero, err := g.zeroCheck(src, fi.typ)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(b, "if %s {\nrow[%d] = \"\"\n} else {\n%s}\n", zero, j, enc)
+	} else {
+		b.WriteString(enc)
+	}
+
+	return b.String(), nil
+}
+
+// encodeValue sets row[j] from a value of type t, with the named parser if
+// it is not empty. Methods are called on recv, and conversions and parsers
+// use value, which differ when the field is a pointer.
+func (g *generator) encodeValue(recv, value string, t types.Type, j int, parser string) (string, error) {
+	kind, basic := g.classify(t, g.textMarshaler)
+
+	// only conversions need the type, and so possibly an import
+	var typ string
+	if basic != nil && parser == "" {
+		typ = g.typeString(t)
+	}
+
+	if parser != "" {
+		return fmt.Sprintf("if v, err := csvmum.Format(%q, %s); err != nil {\nreturn err\n} else {\nrow[%d] = v\n}\n", parser, value, j), nil
+	}
+
+	switch kind {
+	case kindText:
+		return fmt.Sprintf("if b, err := %s.MarshalText(); err != nil {\nreturn err\n} else {\nrow[%d] = string(b)\n}\n", recv, j), nil
+	case kindDuration:
+		return fmt.Sprintf("row[%d] = %s.String()\n", j, recv), nil
+	case kindString:
+		return fmt.Sprintf("row[%d] = %s\n", j, convert("string", typ, value)), nil
+	case kindInt:
+		return fmt.Sprintf("row[%d] = strconv.FormatInt(%s, 10)\n", j, convert("int64", typ, value)), nil
+	case kindUint:
+		return fmt.Sprintf("row[%d] = strconv.FormatUint(%s, 10)\n", j, convert("uint64", typ, value)), nil
+	case kindBool:
+		return fmt.Sprintf("row[%d] = strconv.FormatBool(%s)\n", j, convert("bool", typ, value)), nil
+	case kindFloat:
+		return fmt.Sprintf("row[%d] = strconv.FormatFloat(%s, 'f', -1, %s)\n", j, convert("float64", typ, value), bits(basic)), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+// zeroCheck mirrors reflect.Value.IsZero for the kinds csvmum supports.
+func (g *generator) zeroCheck(src string, t types.Type) (string, error) {
+	if _, ok := t.(*types.Pointer); ok {
+		return src + " == nil", nil
+	}
+
+	if basic, ok := t.Underlying().(*types.Basic); ok {
+		switch {
+		case basic.Info()&types.IsString != 0:
+			return src + ` == ""`, nil
+		case basic.Info()&types.IsBoolean != 0:
+			return "!" + src, nil
+		case basic.Info()&types.IsFloat != 0:
+			return fmt.Sprintf("math.Float64bits(float64(%s)) == 0", src), nil
+		case basic.Info()&types.IsInteger != 0:
+			return src + " == 0", nil
+		}
+	}
+
+	switch t.Underlying().(type) {
+	case *types.Struct, *types.Array:
+		if types.Comparable(t) {
+			return fmt.Sprintf("%s == (%s{})", src, g.typeString(t)), nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported omitempty type %s", t)
+}
+
+// classify mirrors the order compileDecoder and compileEncoder in csvmum check
+// a type in: text interfaces, then time.Duration, then the kind.
+func (g *generator) classify(t types.Type, text *types.Interface) (typeKind, *types.Basic) {
+	if types.Implements(types.NewPointer(t), text) {
+		return kindText, nil
+	}
+
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration" {
+			return kindDuration, nil
+		}
+	}
+
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return kindUnsupported, nil
+	}
+
+	switch basic.Kind() {
+	case types.String:
+		return kindString, basic
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Int64:
+		return kindInt, basic
+	case types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64, types.Uintptr:
+		return kindUint, basic
+	case types.Bool:
+		return kindBool, basic
+	case types.Float32, types.Float64:
+		return kindFloat, basic
+	}
+
+	return kindUnsupported, nil
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+		g.imports[p.Path()] = p.Name()
+		return p.Name()
+	})
+}
+
+// convert returns expr, of type from, converted to type to.
+func convert(to, from, expr string) string {
+	if to == from {
+		return expr
+	}
+	return fmt.Sprintf("%s(%s)", to, expr)
+}
+
+func bits(basic *types.Basic) string {
+	switch basic.Kind() {
+	case types.Int, types.Uint, types.Uintptr:
+		return "strconv.IntSize"
+	case types.Int8, types.Uint8:
+		return "8"
+	case types.Int16, types.Uint16:
+		return "16"
+	case types.Int32, types.Uint32, types.Float32:
+		return "32"
+	}
+	return "64"
+}
+
+// parseError matches the errors returned by compileDecoder in csvmum, which
+// are named after the reflect.Kind.
+func parseError(basic *types.Basic) string {
+	kinds := map[types.BasicKind]string{
+		types.Int:     "int",
+		types.Int8:    "int8",
+		types.Int16:   "int16",
+		types.Int32:   "int32",
+		types.Int64:   "int64",
+		types.Uint:    "uint",
+		types.Uint8:   "uint8",
+		types.Uint16:  "uint16",
+		types.Uint32:  "uint32",
+		types.Uint64:  "uint64",
+		types.Uintptr: "uintptr",
+		types.Bool:    "bool",
+		types.Float32: "float32",
+		types.Float64: "float64",
+	}
+	return fmt.Sprintf(`fmt.Errorf("error parsing %s: %%w", err)`, kinds[basic.Kind()])
+}
@@ -0,0 +1,52 @@
+// Csvmumgen generates reflection-free MarshalCSVRow and UnmarshalCSVRow
+// methods for structs with csv tags, which csvmum uses in place of
+// reflection when they are present.
+//
+// Usage:
+//
+//	//go:generate go run github.com/bridgelightcloud/bogie/tools/csvmumgen [-type T,U] [-output file.go] [dir]
+//
+// Without -type, every struct in the package with at least one csv tag is
+// generated. Structs with fields csvmumgen cannot handle are skipped, and
+// csvmum falls back to reflection for them.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("csvmumgen: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of type names; default all structs with csv tags")
+	output := flag.String("output", "csvmum_gen.go", "output file name, relative to the package directory")
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	var types []string
+	if *typeNames != "" {
+		types = strings.Split(*typeNames, ",")
+	}
+
+	src, skipped, err := generate(dir, *output, types)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, s := range skipped {
+		log.Printf("skipping %s", s)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, *output), src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}